@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -14,6 +15,7 @@ import (
 
 	"github.com/redhat/gramola-operator/pkg/apis"
 	"github.com/redhat/gramola-operator/pkg/controller"
+	"github.com/redhat/gramola-operator/pkg/webhook"
 	"github.com/redhat/gramola-operator/version"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -37,6 +39,12 @@ var (
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
 )
+
+// Webhooks are served on webhookPort with the certificate OLM mounts in webhookCertDir
+var (
+	webhookPort    = 9443
+	webhookCertDir = "/apiserver.local.config/certificates"
+)
 var log = logf.Log.WithName("cmd")
 
 func printVersion() {
@@ -94,6 +102,8 @@ func main() {
 	mgr, err := manager.New(cfg, manager.Options{
 		Namespace:          namespace,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Port:               webhookPort,
+		CertDir:            webhookCertDir,
 	})
 	if err != nil {
 		log.Error(err, "")
@@ -114,6 +124,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup all Webhooks
+	if err := addWebhooks(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	// Add the Metrics Service
 	addMetrics(ctx, cfg, namespace)
 
@@ -126,6 +142,16 @@ func main() {
 	}
 }
 
+// addWebhooks registers the admission webhooks if there's a serving certificate, there's none when the operator runs
+// locally or is deployed without OLM. The controller validates AppServices anyway
+func addWebhooks(mgr manager.Manager) error {
+	if _, err := os.Stat(filepath.Join(webhookCertDir, "tls.crt")); err != nil {
+		log.Info(fmt.Sprintf("Skipping webhooks; no serving certificate in %s.", webhookCertDir))
+		return nil
+	}
+	return webhook.AddToManager(mgr)
+}
+
 // addMetrics will create the Services and Service Monitors to allow the operator export the metrics by using
 // the Prometheus operator
 func addMetrics(ctx context.Context, cfg *rest.Config, namespace string) {
@@ -0,0 +1,11 @@
+// Package db bundles the scripts that update the events database into the operator binary
+package db
+
+import (
+	"embed"
+)
+
+// Scripts contains the SQL scripts and the expected schema manifest in this directory
+//
+//go:embed *.sql *.yaml
+var Scripts embed.FS
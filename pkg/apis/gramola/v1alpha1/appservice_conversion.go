@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the version AppServices are converted through, it is the version the controller works with
+func (*AppService) Hub() {}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,24 +14,240 @@ type AppServiceSpec struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 
-	// Flags if the the AppService object is enabled or not
+	// Flags if the the AppService object is enabled or not, if not events, gateway and frontend (and the events database
+	// unless database.keepRunning is set) are scaled down to zero and restored when enabled again
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enabled"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	Enabled bool `json:"enabled"`
 
-	// Flags if the object has been initialized or not
-	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
-	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Initialized"
-	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
-	Initialized bool `json:"initialized,omitempty"`
-
 	// Different names for Gramola Service
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Alias"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	// +kubebuilder:validation:Enum=Gramola;Gramophone;Phonograph
 	Alias string `json:"alias,omitempty"`
+
+	// Overrides of the branding of the frontend, which defaults to the one of the alias
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Branding"
+	Branding *BrandingSpec `json:"branding,omitempty"`
+
+	// Retention policy to purge past events
+	// +optional
+	Retention *RetentionSpec `json:"retention,omitempty"`
+
+	// Additional migration scripts run in order after Gramola's own
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Migration Sources"
+	MigrationSources []MigrationSource `json:"migrationSources,omitempty"`
+
+	// Events database settings
+	// +optional
+	Database *DatabaseSpec `json:"database,omitempty"`
+
+	// Windows when disruptive actions (image updates, migrations, credential rotation and storage resize)
+	// can run, if empty they run as soon as they are needed
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Maintenance Windows"
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Sleep mode, the AppService is scaled down to zero from sleep to wake time
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Sleep Schedule"
+	Schedule *SleepSchedule `json:"schedule,omitempty"`
+
+	// Delete removes the events database volume with the AppService, Retain keeps the volume and its credentials and
+	// Snapshot exports the events before the AppService is deleted. Defaults to Delete
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Deletion Policy"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Delete"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Retain"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Snapshot"
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Where the events are exported with the Snapshot deletion policy, a ConfigMap named <name>-final-snapshot by default
+	// +optional
+	DeletionSnapshot *DeletionSnapshotSpec `json:"deletionSnapshot,omitempty"`
+}
+
+// MigrationApproval defines how database migrations are approved
+type MigrationApproval string
+
+// MigrationApprovals defined here
+const (
+	MigrationApprovalAutomatic MigrationApproval = "Automatic"
+	MigrationApprovalManual    MigrationApproval = "Manual"
+)
+
+// AppServiceFinalizer is added to AppServices by the controller, it's removed once the AppService is finalized
+const AppServiceFinalizer = "gramola.redhat.com/finalizer"
+
+// MigrationApprovalAnnotation approves the pending migrations when its value is their digest
+const MigrationApprovalAnnotation = "gramola.redhat.com/approved-migrations"
+
+// WakeUpUntilAnnotation keeps the AppService awake, regardless of its sleep schedule, until the RFC 3339 time it holds
+const WakeUpUntilAnnotation = "gramola.redhat.com/wake-up-until"
+
+// ConfigHashAnnotation records in a pod template the hash of the configuration its pods are rolled out with
+const ConfigHashAnnotation = "gramola.redhat.com/config-hash"
+
+// ScaledDownReplicasAnnotation records in a Deployment scaled down to zero the replicas to restore
+const ScaledDownReplicasAnnotation = "gramola.redhat.com/scaled-down-replicas"
+
+// LegacyNamesAnnotation records if the objects of the AppService keep the unprefixed names (events, events-database...)
+// they had before their names were prefixed by the name of the AppService. The controller sets it once, true or false
+const LegacyNamesAnnotation = "gramola.redhat.com/legacy-names"
+
+// RetainedFromAnnotation records in an object retained after its AppService was deleted the name of the AppService
+const RetainedFromAnnotation = "gramola.redhat.com/retained-from"
+
+// DeletionPolicy defines what happens to the events when the AppService is deleted
+type DeletionPolicy string
+
+// DeletionPolicies defined here
+const (
+	DeletionPolicyDelete   DeletionPolicy = "Delete"
+	DeletionPolicyRetain   DeletionPolicy = "Retain"
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
+// DeletionSnapshotSpec defines the final export of the events with the Snapshot deletion policy
+type DeletionSnapshotSpec struct {
+	// Format of the exported file, defaults to JSONLines
+	// +optional
+	// +kubebuilder:validation:Enum=JSONLines;CSV
+	Format DataFormat `json:"format,omitempty"`
+
+	// Where the exported file is written
+	Storage DataStorage `json:"storage"`
+}
+
+// GetDeletionPolicy returns the deletion policy, Delete if not set
+func (s *AppServiceSpec) GetDeletionPolicy() DeletionPolicy {
+	if len(s.DeletionPolicy) == 0 {
+		return DeletionPolicyDelete
+	}
+	return s.DeletionPolicy
+}
+
+// ReadOnlyMode defines when the events database is read-only
+type ReadOnlyMode string
+
+// ReadOnlyModes defined here
+const (
+	ReadOnlyModeNever            ReadOnlyMode = "Never"
+	ReadOnlyModeDuringOperations ReadOnlyMode = "DuringOperations"
+	ReadOnlyModeAlways           ReadOnlyMode = "Always"
+)
+
+// DatabaseSpec defines the events database settings
+type DatabaseSpec struct {
+	// Automatic runs migrations as soon as they are found, Manual waits until the digest of the pending
+	// migrations is set in the gramola.redhat.com/approved-migrations annotation. Defaults to Automatic
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Migration Approval"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Automatic"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Manual"
+	MigrationApproval MigrationApproval `json:"migrationApproval,omitempty"`
+
+	// Size of the events database volume, defaults to 512Mi. It can grow if the storage class allows expansion
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Database Storage"
+	Storage *resource.Quantity `json:"storage,omitempty"`
+
+	// Storage class of the events database volume, the default one if empty. It can't change once set
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Database Storage Class"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:StorageClass"
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Never keeps the events database read-write, DuringOperations makes it read-only while backups (AppServiceDataExports)
+	// and migrations run and Always keeps it read-only. Defaults to Never
+	// +kubebuilder:validation:Enum=Never;DuringOperations;Always
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Read Only"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Never"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:DuringOperations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Always"
+	ReadOnly ReadOnlyMode `json:"readOnly,omitempty"`
+
+	// Keeps the events database running while the rest of the AppService is scaled down to zero
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Keep Database Running"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	KeepRunning bool `json:"keepRunning,omitempty"`
+}
+
+// IsMigrationApprovalManual returns true if migrations wait for approval
+func (s *AppServiceSpec) IsMigrationApprovalManual() bool {
+	return s.Database != nil && s.Database.MigrationApproval == MigrationApprovalManual
+}
+
+// GetReadOnlyMode returns when the events database is read-only, Never by default
+func (s *AppServiceSpec) GetReadOnlyMode() ReadOnlyMode {
+	if s.Database == nil || len(s.Database.ReadOnly) == 0 {
+		return ReadOnlyModeNever
+	}
+	return s.Database.ReadOnly
+}
+
+// RetentionSpec defines how long past events are kept
+type RetentionSpec struct {
+	// Days events are kept after their (end) date
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Retention Days"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	Days int32 `json:"days"`
+
+	// Schedule of the purge in Cron format, defaults to every day at 03:00
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Retention Schedule"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// BrandingSpec overrides the branding of the frontend
+type BrandingSpec struct {
+	// Title shown by the frontend
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Title"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Title string `json:"title,omitempty"`
+
+	// URL of the logo shown by the frontend
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Logo URL"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	LogoURL string `json:"logoURL,omitempty"`
+
+	// Primary colour of the frontend, e.g. #cc0000
+	// +optional
+	// +kubebuilder:validation:Pattern=`^#[0-9a-fA-F]{6}$`
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Primary Colour"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	PrimaryColor string `json:"primaryColor,omitempty"`
+
+	// Accent colour of the frontend, e.g. #f0ab00
+	// +optional
+	// +kubebuilder:validation:Pattern=`^#[0-9a-fA-F]{6}$`
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Accent Colour"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	AccentColor string `json:"accentColor,omitempty"`
 }
 
 // AppServiceConditionType defines the potential condition types
@@ -38,7 +255,12 @@ type AppServiceConditionType string
 
 // AppServiceConditionTypes defined here
 const (
-	AppServiceConditionTypePromoted AppServiceConditionType = "Promoted"
+	AppServiceConditionTypeAvailable        AppServiceConditionType = "Available"
+	AppServiceConditionTypeProgressing      AppServiceConditionType = "Progressing"
+	AppServiceConditionTypeDegraded         AppServiceConditionType = "Degraded"
+	AppServiceConditionTypePromoted         AppServiceConditionType = "Promoted"
+	AppServiceConditionTypeMigrationPending AppServiceConditionType = "MigrationPending"
+	AppServiceConditionTypeReadOnly         AppServiceConditionType = "ReadOnly"
 )
 
 // AppServiceConditionReason defines the potential condition reasons
@@ -65,10 +287,17 @@ const (
 	AppServiceConditionStatusUnknown AppServiceConditionStatus = "Unknown"
 )
 
+// Statuses of a reconciliation defined here
+const (
+	ReconcileStatusSucceeded   AppServiceConditionStatus = "Succeeded"
+	ReconcileStatusProgressing AppServiceConditionStatus = "Progressing"
+	ReconcileStatusFailed      AppServiceConditionStatus = "Failed"
+)
+
 // AppServiceCondition defines the desired state
 type AppServiceCondition struct {
 	// Type of replication controller condition.
-	// +kubebuilder:validation:Enum=Promoted
+	// +kubebuilder:validation:Enum=Available;Progressing;Degraded;Promoted;MigrationPending;ReadOnly
 	Type AppServiceConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=AppServiceConditionType"`
 	// Status of the condition, one of True, False, Unknown.
 	// +kubebuilder:validation:Enum=True;False;Unknown
@@ -85,15 +314,19 @@ type AppServiceCondition struct {
 	Message string `json:"message,omitempty" protobuf:"bytes,5,opt,name=message"`
 }
 
-// ReconcileStatus defines the reconciliation status
+// ReconcileStatus defines the reconciliation status, it tells if the spec was applied while the Available condition
+// tells if the AppService is ready
 type ReconcileStatus struct {
-	// Status shows the reconcile run
-	// +kubebuilder:validation:Enum=Succeded;Progressing;Failed;True
+	// Status shows the reconcile run, Succeeded once the whole spec is applied, Progressing while the reconciliation
+	// waits for something (the database to be ready, a maintenance window) and Failed on errors
+	// +kubebuilder:validation:Enum=Succeeded;Progressing;Failed
 	Status AppServiceConditionStatus `json:"status,omitempty"`
 	// LastUpdate records the last time an update was regitered
 	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
 	// Reason for the update or change in status
 	Reason string `json:"reason,omitempty"`
+	// Failed reconciliations in a row, retries back off exponentially with them
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
 }
 
 // ActionType defines the potential actions types
@@ -124,6 +357,97 @@ type DatabaseScriptRun struct {
 	// Status of the run of the Script
 	// +kubebuilder:validation:Enum=Succeeded;Failed;Unknown
 	Status DatabaseUpdateStatus `json:"eventsDatabaseUpdated,omitempty"`
+
+	// Error rendering or running the Script
+	Message string `json:"message,omitempty"`
+}
+
+// RetentionStatus shows the result of the last purge of past events
+type RetentionStatus struct {
+	// Job of the last purge
+	LastJob string `json:"lastJob,omitempty"`
+
+	// Time the last purge finished
+	LastRunTime metav1.Time `json:"lastRunTime,omitempty"`
+
+	// Status of the last purge
+	// +kubebuilder:validation:Enum=Succeeded;Failed;Unknown
+	LastRunStatus DatabaseUpdateStatus `json:"lastRunStatus,omitempty"`
+
+	// Number of events deleted by the last purge
+	LastPurgedRows int64 `json:"lastPurgedRows"`
+
+	// A human readable message, the error if the last purge failed
+	Message string `json:"message,omitempty"`
+}
+
+// PendingMigrations lists the migrations waiting for approval
+type PendingMigrations struct {
+	// Migrations pending in run order
+	Scripts []string `json:"scripts"`
+
+	// Digest of the pending migrations, set it in the gramola.redhat.com/approved-migrations annotation to run them
+	Digest string `json:"digest"`
+}
+
+// Lineage records where the events of an AppService were cloned from
+type Lineage struct {
+	// Namespace of the source AppService
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// Name of the source AppService
+	SourceName string `json:"sourceName"`
+
+	// AppServiceClone that copied the events
+	Clone string `json:"clone"`
+
+	// Time the copy finished
+	ClonedAt metav1.Time `json:"clonedAt"`
+
+	// Columns masked during the copy
+	MaskedColumns []string `json:"maskedColumns,omitempty"`
+}
+
+// ComponentStatus defines the health of a component (Deployment) of an AppService
+type ComponentStatus struct {
+	// Name of the component, events, events-database, gateway or frontend
+	Name string `json:"name"`
+
+	// Replicas desired
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Replicas ready
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Image the component runs
+	Image string `json:"image,omitempty"`
+
+	// Host of the Route of the component, if exposed
+	// +optional
+	RouteHost string `json:"routeHost,omitempty"`
+
+	// Last warning found in the container statuses of the pods, e.g. CrashLoopBackOff or ImagePullBackOff,
+	// empty if they are healthy
+	// +optional
+	LastWarning string `json:"lastWarning,omitempty"`
+}
+
+// AppServiceURLs defines the URLs of the Routes of an AppService
+type AppServiceURLs struct {
+	// URL of the frontend
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Frontend URL"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:org.w3:link"
+	Frontend string `json:"frontend,omitempty"`
+
+	// URL of the gateway API
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// URL of the events API
+	// +optional
+	Events string `json:"events,omitempty"`
 }
 
 // AppServiceStatus defines the observed state of AppService
@@ -133,6 +457,16 @@ type AppServiceStatus struct {
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 	ReconcileStatus `json:",inline"`
 
+	// Generation of the spec last applied, it equals metadata.generation once the reconciliation Succeeded
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Version of the operator that last reconciled the AppService, older operators can't take it over
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Operator Version"
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
 	// Indicates if the Events Database has been updated or not
 	// +kubebuilder:validation:Enum=Succeeded;Failed;Unknown
 	EventsDatabaseUpdated DatabaseUpdateStatus `json:"eventsDatabaseUpdated,omitempty"`
@@ -140,6 +474,50 @@ type AppServiceStatus struct {
 	// List of Event Database Scripts Runs
 	EventsDatabaseScriptRuns []DatabaseScriptRun `json:"eventsDatabaseScriptRuns,omitempty"`
 
+	// Result of the last purge of past events
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Retention"
+	Retention *RetentionStatus `json:"retention,omitempty"`
+
+	// Migrations waiting for approval
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Pending Migrations"
+	PendingMigrations *PendingMigrations `json:"pendingMigrations,omitempty"`
+
+	// Disruptive actions waiting for a maintenance window
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Pending Actions"
+	PendingActions []PendingAction `json:"pendingActions,omitempty"`
+
+	// Start of the next maintenance window, the current one if open
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Next Maintenance Window"
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+
+	// Sleep state, if a sleep schedule is set
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Sleep"
+	Sleep *SleepStatus `json:"sleep,omitempty"`
+
+	// Last window the frontend was replaced by the maintenance page during a migration or restore
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Maintenance Page"
+	MaintenancePage *MaintenancePageStatus `json:"maintenancePage,omitempty"`
+
+	// Health of each component
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Components"
+	Components []ComponentStatus `json:"components,omitempty"`
+
+	// URLs of the application, from the hosts admitted for its Routes
+	// +optional
+	URLs *AppServiceURLs `json:"urls,omitempty"`
+
+	// Source of the events if they were cloned from another AppService
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Lineage"
+	Lineage *Lineage `json:"lineage,omitempty"`
+
 	// Last Action run
 	// +kubebuilder:validation:Enum=BackupStarted;NoAction;RequeueEvent
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
@@ -151,7 +529,39 @@ type AppServiceStatus struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="AppService Conditions"
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes.conditions"
-	Conditions []AppServiceCondition `json:"conditions,omitempty"` // Used to wait => kubectl wait canary/podinfo --for=condition=promoted
+	Conditions []AppServiceCondition `json:"conditions,omitempty"` // Used to wait => oc wait appservice/gramola --for=condition=Available
+}
+
+// SetCondition sets the status, reason and message of a condition, the transition time changes with the status
+func (s *AppServiceStatus) SetCondition(conditionType AppServiceConditionType, status AppServiceConditionStatus, reason AppServiceConditionReason, message string) {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			if s.Conditions[i].Status != status {
+				s.Conditions[i].LastTransitionTime = metav1.Now()
+			}
+			s.Conditions[i].Status = status
+			s.Conditions[i].Reason = reason
+			s.Conditions[i].Message = message
+			return
+		}
+	}
+	s.Conditions = append(s.Conditions, AppServiceCondition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// GetCondition returns a condition, nil if not set
+func (s *AppServiceStatus) GetCondition(conditionType AppServiceConditionType) *AppServiceCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -160,6 +570,14 @@ type AppServiceStatus struct {
 // +operator-sdk:gen-csv:customresourcedefinitions.displayName="AppService"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=appservices,scope=Namespaced
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type==\"Available\")].status"
+// +kubebuilder:printcolumn:name="Events",type="integer",JSONPath=".status.components[?(@.name==\"events\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Database",type="integer",JSONPath=".status.components[?(@.name==\"events-database\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Gateway",type="integer",JSONPath=".status.components[?(@.name==\"gateway\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Frontend",type="integer",JSONPath=".status.components[?(@.name==\"frontend\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".status.components[?(@.name==\"frontend\")].routeHost"
+// +kubebuilder:printcolumn:name="Warnings",type="string",JSONPath=".status.components[*].lastWarning",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type AppService struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaskingStrategy defines how the values of a column are masked
+type MaskingStrategy string
+
+// MaskingStrategies defined here
+const (
+	// MaskingStrategyRedact replaces every character with '*'
+	MaskingStrategyRedact MaskingStrategy = "Redact"
+	// MaskingStrategyHash replaces values with their MD5 hash, equal values keep being equal
+	MaskingStrategyHash MaskingStrategy = "Hash"
+	// MaskingStrategyNull removes values
+	MaskingStrategyNull MaskingStrategy = "Null"
+	// MaskingStrategyFixed replaces values with a fixed one
+	MaskingStrategyFixed MaskingStrategy = "Fixed"
+)
+
+// MaskingRule defines how a column of public.event is masked during the copy
+type MaskingRule struct {
+	// Column of public.event, the id can't be masked
+	Column string `json:"column"`
+	// Masking strategy
+	// +kubebuilder:validation:Enum=Redact;Hash;Null;Fixed
+	Strategy MaskingStrategy `json:"strategy"`
+	// Value used by the Fixed strategy
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// AppServiceReference refers to an AppService, possibly in another namespace
+type AppServiceReference struct {
+	// Name of the AppService
+	Name string `json:"name"`
+	// Namespace of the AppService, defaults to the namespace of the referrer. The operator must watch it
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// AppServiceCloneSpec defines the desired state of AppServiceClone
+type AppServiceCloneSpec struct {
+	// AppService whose events database is copied
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Source"
+	Source AppServiceReference `json:"source"`
+
+	// Name of the AppService (in the same namespace) whose events are replaced by the copy
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Target"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:gramola.redhat.com:v1alpha1:AppService"
+	Target string `json:"target"`
+
+	// Masking rules applied to the columns during the copy
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Masking Rules"
+	MaskingRules []MaskingRule `json:"maskingRules,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceClone is the Schema for the appserviceclones API copies the events of an AppService into another one
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="AppService Clone"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=appserviceclones,scope=Namespaced
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.target"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Rows",type="integer",JSONPath=".status.rows"
+type AppServiceClone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppServiceCloneSpec `json:"spec,omitempty"`
+	Status DataTransferStatus  `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceCloneList contains a list of AppServiceClone
+type AppServiceCloneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppServiceClone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppServiceClone{}, &AppServiceCloneList{})
+}
@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppServiceDataExportSpec defines the desired state of AppServiceDataExport
+type AppServiceDataExportSpec struct {
+	// Name of the AppService (in the same namespace) whose events are exported
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="AppService"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:gramola.redhat.com:v1alpha1:AppService"
+	AppService string `json:"appService"`
+
+	// Format of the exported file
+	// +kubebuilder:validation:Enum=JSONLines;CSV
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Format"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:JSONLines"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:CSV"
+	Format DataFormat `json:"format"`
+
+	// Where the exported file is written
+	Storage DataStorage `json:"storage"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceDataExport is the Schema for the appservicedataexports API exports the events of an AppService
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="AppService Data Export"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=appservicedataexports,scope=Namespaced
+// +kubebuilder:printcolumn:name="AppService",type="string",JSONPath=".spec.appService"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Rows",type="integer",JSONPath=".status.rows"
+type AppServiceDataExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppServiceDataExportSpec `json:"spec,omitempty"`
+	Status DataTransferStatus       `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceDataExportList contains a list of AppServiceDataExport
+type AppServiceDataExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppServiceDataExport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppServiceDataExport{}, &AppServiceDataExportList{})
+}
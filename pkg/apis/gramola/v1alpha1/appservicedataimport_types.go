@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DataImportMode defines how imported events are merged with the existing ones
+type DataImportMode string
+
+// DataImportModes defined here
+const (
+	DataImportModeUpsert  DataImportMode = "Upsert"
+	DataImportModeReplace DataImportMode = "Replace"
+)
+
+// AppServiceDataImportSpec defines the desired state of AppServiceDataImport
+type AppServiceDataImportSpec struct {
+	// Name of the AppService (in the same namespace) receiving the events
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="AppService"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:gramola.redhat.com:v1alpha1:AppService"
+	AppService string `json:"appService"`
+
+	// Format of the imported file
+	// +kubebuilder:validation:Enum=JSONLines;CSV
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Format"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:JSONLines"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:CSV"
+	Format DataFormat `json:"format"`
+
+	// Upsert inserts new events and updates the ones with the same id, Replace deletes all the events first
+	// +kubebuilder:validation:Enum=Upsert;Replace
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Mode"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Upsert"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Replace"
+	Mode DataImportMode `json:"mode,omitempty"`
+
+	// Where the file to import is read from
+	Storage DataStorage `json:"storage"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceDataImport is the Schema for the appservicedataimports API imports events into an AppService
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="AppService Data Import"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=appservicedataimports,scope=Namespaced
+// +kubebuilder:printcolumn:name="AppService",type="string",JSONPath=".spec.appService"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Rows",type="integer",JSONPath=".status.rows"
+type AppServiceDataImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppServiceDataImportSpec `json:"spec,omitempty"`
+	Status DataTransferStatus       `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceDataImportList contains a list of AppServiceDataImport
+type AppServiceDataImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppServiceDataImport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppServiceDataImport{}, &AppServiceDataImportList{})
+}
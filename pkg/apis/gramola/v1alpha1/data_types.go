@@ -0,0 +1,109 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DataFormat defines the potential formats of exported event data
+type DataFormat string
+
+// DataFormats defined here
+const (
+	DataFormatJSONLines DataFormat = "JSONLines"
+	DataFormatCSV       DataFormat = "CSV"
+)
+
+// DataPersistentVolumeClaimStorage locates a file in a PersistentVolumeClaim
+type DataPersistentVolumeClaimStorage struct {
+	// Name of the PersistentVolumeClaim in the same namespace
+	ClaimName string `json:"claimName"`
+	// Path of the file relative to the root of the volume, defaults to events.jsonl or events.csv
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// DataConfigMapStorage locates a key in a ConfigMap
+type DataConfigMapStorage struct {
+	// Name of the ConfigMap in the same namespace
+	Name string `json:"name"`
+	// Key holding the data, defaults to events.jsonl or events.csv
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DataStorage defines where event data is stored, only one of the fields should be set
+type DataStorage struct {
+	// File in a PersistentVolumeClaim, preferred for big catalogues
+	// +optional
+	PersistentVolumeClaim *DataPersistentVolumeClaimStorage `json:"persistentVolumeClaim,omitempty"`
+	// Key in a ConfigMap, limited to 1MiB of data
+	// +optional
+	ConfigMap *DataConfigMapStorage `json:"configMap,omitempty"`
+}
+
+// validDataPath matches the paths and keys accepted, they end up quoted in SQL
+var validDataPath = regexp.MustCompile(`^[A-Za-z0-9._-][A-Za-z0-9._/-]*$`)
+
+// Validate checks that exactly one storage is set and its path or key is safe
+func (s *DataStorage) Validate() error {
+	if (s.ConfigMap == nil) == (s.PersistentVolumeClaim == nil) {
+		return fmt.Errorf("Exactly one of storage.configMap and storage.persistentVolumeClaim must be set")
+	}
+	if s.ConfigMap != nil && len(s.ConfigMap.Key) > 0 && !validDataPath.MatchString(s.ConfigMap.Key) {
+		return fmt.Errorf("Invalid ConfigMap key %s", s.ConfigMap.Key)
+	}
+	if s.PersistentVolumeClaim != nil && len(s.PersistentVolumeClaim.Path) > 0 {
+		if !validDataPath.MatchString(s.PersistentVolumeClaim.Path) || strings.Contains("/"+s.PersistentVolumeClaim.Path+"/", "/../") {
+			return fmt.Errorf("Invalid path %s", s.PersistentVolumeClaim.Path)
+		}
+	}
+	return nil
+}
+
+// DataTransferPhase defines the potential phases of an export or import
+type DataTransferPhase string
+
+// DataTransferPhases defined here
+const (
+	DataTransferPhasePending   DataTransferPhase = "Pending"
+	DataTransferPhaseRunning   DataTransferPhase = "Running"
+	DataTransferPhaseSucceeded DataTransferPhase = "Succeeded"
+	DataTransferPhaseFailed    DataTransferPhase = "Failed"
+)
+
+// DataTransferStatus defines the observed state of an export or import
+type DataTransferStatus struct {
+	// Phase of the transfer
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Phase"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes.phase"
+	Phase DataTransferPhase `json:"phase,omitempty"`
+
+	// Time the transfer started
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// Time the transfer succeeded or failed
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+
+	// Number of events transferred
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Rows"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:text"
+	Rows int64 `json:"rows,omitempty"`
+
+	// Job running the transfer when the storage is a PersistentVolumeClaim
+	Job string `json:"job,omitempty"`
+
+	// A human readable message, the error if the transfer failed
+	Message string `json:"message,omitempty"`
+}
+
+// IsFinished returns true if the transfer succeeded or failed
+func (s *DataTransferStatus) IsFinished() bool {
+	return s.Phase == DataTransferPhaseSucceeded || s.Phase == DataTransferPhaseFailed
+}
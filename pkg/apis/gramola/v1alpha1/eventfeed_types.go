@@ -0,0 +1,125 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventFeedFormat defines the potential formats of an external feed
+type EventFeedFormat string
+
+// EventFeedFormats defined here
+const (
+	EventFeedFormatICal EventFeedFormat = "ICal"
+	EventFeedFormatJSON EventFeedFormat = "JSON"
+)
+
+// EventFeedSpec defines the desired state of EventFeed
+type EventFeedSpec struct {
+	// URL of the external feed
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="URL"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	URL string `json:"url"`
+
+	// Format of the external feed
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Format"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:ICal"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:JSON"
+	// +kubebuilder:validation:Enum=ICal;JSON
+	Format EventFeedFormat `json:"format"`
+
+	// Name of the AppService (in the same namespace) whose gateway receives the events
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="AppService"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:gramola.redhat.com:v1alpha1:AppService"
+	AppService string `json:"appService"`
+
+	// Schedule in Cron format, defaults to every hour
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Schedule"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Schedule string `json:"schedule,omitempty"`
+
+	// Maps Gramola event fields (name, artist, startDate...) onto feed fields. Nested JSON
+	// fields are separated by dots, iCal date properties expose '.date' and '.time' suffixes
+	// +optional
+	FieldMapping map[string]string `json:"fieldMapping,omitempty"`
+
+	// Dot separated path to the array of entries in a JSON feed, empty if the document is the array
+	// +optional
+	ItemsPath string `json:"itemsPath,omitempty"`
+}
+
+// EventFeedStatus defines the observed state of EventFeed
+type EventFeedStatus struct {
+	// Generation of the spec last imported
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Last time the feed was fetched
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Last Fetch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:text"
+	LastFetchTime metav1.Time `json:"lastFetchTime,omitempty"`
+
+	// Last time the feed was fetched and imported without errors
+	LastSuccessfulFetchTime metav1.Time `json:"lastSuccessfulFetchTime,omitempty"`
+
+	// Error found fetching, parsing or importing the feed, empty if the last run succeeded
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Last Error"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:text"
+	LastError string `json:"lastError,omitempty"`
+
+	// Entries found in the feed in the last run
+	Entries int32 `json:"entries"`
+
+	// Events created in the last run
+	Created int32 `json:"created"`
+
+	// Events updated in the last run
+	Updated int32 `json:"updated"`
+
+	// Entries already up to date in the last run
+	Unchanged int32 `json:"unchanged"`
+
+	// Entries that could not be mapped or upserted in the last run
+	Failed int32 `json:"failed"`
+
+	// Events created or updated since the feed was first imported
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Total Imported"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:text"
+	TotalImported int64 `json:"totalImported"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventFeed is the Schema for the eventfeeds API periodically imports events from an external feed
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="EventFeed"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=eventfeeds,scope=Namespaced
+// +kubebuilder:printcolumn:name="Format",type="string",JSONPath=".spec.format"
+// +kubebuilder:printcolumn:name="Last Fetch",type="date",JSONPath=".status.lastFetchTime"
+// +kubebuilder:printcolumn:name="Imported",type="integer",JSONPath=".status.totalImported"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.lastError"
+type EventFeed struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventFeedSpec   `json:"spec,omitempty"`
+	Status EventFeedStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventFeedList contains a list of EventFeed
+type EventFeedList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventFeed `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EventFeed{}, &EventFeedList{})
+}
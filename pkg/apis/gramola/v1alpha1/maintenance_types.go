@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Weekday defines the days of the week of a maintenance window
+type Weekday string
+
+// Weekdays defined here
+const (
+	Sunday    Weekday = "Sunday"
+	Monday    Weekday = "Monday"
+	Tuesday   Weekday = "Tuesday"
+	Wednesday Weekday = "Wednesday"
+	Thursday  Weekday = "Thursday"
+	Friday    Weekday = "Friday"
+	Saturday  Weekday = "Saturday"
+)
+
+// MaintenanceWindow defines when disruptive actions can run, it opens following a Cron schedule
+// or on some days at a start time
+type MaintenanceWindow struct {
+	// Start of the window in Cron format, e.g. "0 2 * * 6" for Saturdays at 02:00
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Days of the week the window opens, used with start when schedule is empty
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Time the window opens in HH:MM format, used with days when schedule is empty
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// How long the window stays open, e.g. 2h
+	Duration metav1.Duration `json:"duration"`
+
+	// IANA time zone of the schedule or start time, defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MaintenanceActionType defines the disruptive actions deferred to maintenance windows
+type MaintenanceActionType string
+
+// MaintenanceActionTypes defined here
+const (
+	MaintenanceActionImageUpdate        MaintenanceActionType = "ImageUpdate"
+	MaintenanceActionMigration          MaintenanceActionType = "Migration"
+	MaintenanceActionCredentialRotation MaintenanceActionType = "CredentialRotation"
+	MaintenanceActionStorageResize      MaintenanceActionType = "StorageResize"
+	MaintenanceActionRollout            MaintenanceActionType = "Rollout"
+)
+
+// PendingAction is a disruptive action waiting for a maintenance window
+type PendingAction struct {
+	// Type of action
+	Type MaintenanceActionType `json:"type"`
+
+	// What the action changes
+	Description string `json:"description"`
+
+	// Start of the window the action is scheduled for
+	ScheduledAt metav1.Time `json:"scheduledAt"`
+}
+
+// MaintenancePageReason defines why the frontend Route points to the maintenance page
+type MaintenancePageReason string
+
+// MaintenancePageReasons defined here
+const (
+	MaintenancePageReasonMigration MaintenancePageReason = "Migration"
+	MaintenancePageReasonRestore   MaintenancePageReason = "Restore"
+)
+
+// MaintenancePageStatus records the last time the frontend Route pointed to the maintenance page
+type MaintenancePageStatus struct {
+	// Flags if the frontend Route points to the maintenance page now
+	Active bool `json:"active"`
+
+	// Why the maintenance page was shown
+	Reason MaintenancePageReason `json:"reason"`
+
+	// Operation that showed the maintenance page, migrations or the name of the AppServiceDataImport
+	Operation string `json:"operation"`
+
+	// When the maintenance page was shown
+	StartTime metav1.Time `json:"startTime"`
+
+	// When the frontend was back
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// SleepSchedule defines when the AppService sleeps, scaled down to zero, and wakes up
+type SleepSchedule struct {
+	// When the AppService goes to sleep in Cron format, e.g. "0 20 * * 1-5" for weekdays at 20:00
+	Sleep string `json:"sleep"`
+
+	// When the AppService wakes up in Cron format, e.g. "0 8 * * 1-5" for weekdays at 08:00
+	Wake string `json:"wake"`
+
+	// IANA time zone of sleep and wake, defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// SleepStatus shows the sleep state of the AppService
+type SleepStatus struct {
+	// Flags if the AppService is asleep, scaled down to zero
+	Asleep bool `json:"asleep"`
+
+	// When the AppService goes to sleep or wakes up next following its schedule
+	NextTransition metav1.Time `json:"nextTransition"`
+
+	// Time until the AppService is kept awake by the gramola.redhat.com/wake-up-until annotation
+	// +optional
+	WakeUpUntil *metav1.Time `json:"wakeUpUntil,omitempty"`
+}
@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MigrationSource locates additional SQL scripts run against the events database after Gramola's own,
+// exactly one of ConfigMap and Image must be set
+type MigrationSource struct {
+	// Name of the source, its scripts are tracked as <name>.<script>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+
+	// Name of a ConfigMap in the namespace, every key ending in .sql is a script
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// OCI image holding the scripts
+	// +optional
+	Image *ImageMigrationSource `json:"image,omitempty"`
+}
+
+// ImageMigrationSource is an OCI image holding .sql files, it must provide sh and cp to copy them out
+type ImageMigrationSource struct {
+	// Image reference
+	Image string `json:"image"`
+
+	// Absolute path of the directory holding the scripts, defaults to /migrations
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+var validMigrationSourceName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+var validMigrationPath = regexp.MustCompile(`^/[A-Za-z0-9._/-]*$`)
+
+// Validate checks that exactly one of ConfigMap and Image is set and the name and path are safe
+func (s *MigrationSource) Validate() error {
+	if !validMigrationSourceName.MatchString(s.Name) || len(s.Name) > 40 {
+		return fmt.Errorf("Invalid migration source name %s", s.Name)
+	}
+	if (len(s.ConfigMap) == 0) == (s.Image == nil) {
+		return fmt.Errorf("Exactly one of configMap and image must be set in migration source %s", s.Name)
+	}
+	if s.Image != nil {
+		if len(s.Image.Image) == 0 {
+			return fmt.Errorf("Image of migration source %s can't be empty", s.Name)
+		}
+		if len(s.Image.Path) > 0 && (!validMigrationPath.MatchString(s.Image.Path) || strings.Contains(s.Image.Path+"/", "/../")) {
+			return fmt.Errorf("Invalid path %s in migration source %s", s.Image.Path, s.Name)
+		}
+	}
+	return nil
+}
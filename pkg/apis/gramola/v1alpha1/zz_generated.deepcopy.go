@@ -14,7 +14,7 @@ func (in *AppService) DeepCopyInto(out *AppService) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -37,6 +37,89 @@ func (in *AppService) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceClone) DeepCopyInto(out *AppServiceClone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceClone.
+func (in *AppServiceClone) DeepCopy() *AppServiceClone {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceClone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppServiceClone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceCloneList) DeepCopyInto(out *AppServiceCloneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AppServiceClone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceCloneList.
+func (in *AppServiceCloneList) DeepCopy() *AppServiceCloneList {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceCloneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppServiceCloneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceCloneSpec) DeepCopyInto(out *AppServiceCloneSpec) {
+	*out = *in
+	out.Source = in.Source
+	if in.MaskingRules != nil {
+		in, out := &in.MaskingRules, &out.MaskingRules
+		*out = make([]MaskingRule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceCloneSpec.
+func (in *AppServiceCloneSpec) DeepCopy() *AppServiceCloneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceCloneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppServiceCondition) DeepCopyInto(out *AppServiceCondition) {
 	*out = *in
@@ -54,6 +137,162 @@ func (in *AppServiceCondition) DeepCopy() *AppServiceCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceDataExport) DeepCopyInto(out *AppServiceDataExport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceDataExport.
+func (in *AppServiceDataExport) DeepCopy() *AppServiceDataExport {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceDataExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppServiceDataExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceDataExportList) DeepCopyInto(out *AppServiceDataExportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AppServiceDataExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceDataExportList.
+func (in *AppServiceDataExportList) DeepCopy() *AppServiceDataExportList {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceDataExportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppServiceDataExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceDataExportSpec) DeepCopyInto(out *AppServiceDataExportSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceDataExportSpec.
+func (in *AppServiceDataExportSpec) DeepCopy() *AppServiceDataExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceDataExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceDataImport) DeepCopyInto(out *AppServiceDataImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceDataImport.
+func (in *AppServiceDataImport) DeepCopy() *AppServiceDataImport {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceDataImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppServiceDataImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceDataImportList) DeepCopyInto(out *AppServiceDataImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AppServiceDataImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceDataImportList.
+func (in *AppServiceDataImportList) DeepCopy() *AppServiceDataImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceDataImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppServiceDataImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceDataImportSpec) DeepCopyInto(out *AppServiceDataImportSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceDataImportSpec.
+func (in *AppServiceDataImportSpec) DeepCopy() *AppServiceDataImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceDataImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppServiceList) DeepCopyInto(out *AppServiceList) {
 	*out = *in
@@ -87,9 +326,64 @@ func (in *AppServiceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServiceReference) DeepCopyInto(out *AppServiceReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceReference.
+func (in *AppServiceReference) DeepCopy() *AppServiceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServiceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppServiceSpec) DeepCopyInto(out *AppServiceSpec) {
 	*out = *in
+	if in.Branding != nil {
+		in, out := &in.Branding, &out.Branding
+		*out = new(BrandingSpec)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionSpec)
+		**out = **in
+	}
+	if in.MigrationSources != nil {
+		in, out := &in.MigrationSources, &out.MigrationSources
+		*out = make([]MigrationSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(DatabaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(SleepSchedule)
+		**out = **in
+	}
+	if in.DeletionSnapshot != nil {
+		in, out := &in.DeletionSnapshot, &out.DeletionSnapshot
+		*out = new(DeletionSnapshotSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -112,6 +406,52 @@ func (in *AppServiceStatus) DeepCopyInto(out *AppServiceStatus) {
 		*out = make([]DatabaseScriptRun, len(*in))
 		copy(*out, *in)
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingMigrations != nil {
+		in, out := &in.PendingMigrations, &out.PendingMigrations
+		*out = new(PendingMigrations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingActions != nil {
+		in, out := &in.PendingActions, &out.PendingActions
+		*out = make([]PendingAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.Sleep != nil {
+		in, out := &in.Sleep, &out.Sleep
+		*out = new(SleepStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenancePage != nil {
+		in, out := &in.MaintenancePage, &out.MaintenancePage
+		*out = new(MaintenancePageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = new(AppServiceURLs)
+		**out = **in
+	}
+	if in.Lineage != nil {
+		in, out := &in.Lineage, &out.Lineage
+		*out = new(Lineage)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]AppServiceCondition, len(*in))
@@ -133,34 +473,524 @@ func (in *AppServiceStatus) DeepCopy() *AppServiceStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseScriptRun) DeepCopyInto(out *DatabaseScriptRun) {
+func (in *AppServiceURLs) DeepCopyInto(out *AppServiceURLs) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseScriptRun.
-func (in *DatabaseScriptRun) DeepCopy() *DatabaseScriptRun {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServiceURLs.
+func (in *AppServiceURLs) DeepCopy() *AppServiceURLs {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseScriptRun)
+	out := new(AppServiceURLs)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReconcileStatus) DeepCopyInto(out *ReconcileStatus) {
+func (in *BrandingSpec) DeepCopyInto(out *BrandingSpec) {
 	*out = *in
-	in.LastUpdate.DeepCopyInto(&out.LastUpdate)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileStatus.
-func (in *ReconcileStatus) DeepCopy() *ReconcileStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrandingSpec.
+func (in *BrandingSpec) DeepCopy() *BrandingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReconcileStatus)
+	out := new(BrandingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataConfigMapStorage) DeepCopyInto(out *DataConfigMapStorage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataConfigMapStorage.
+func (in *DataConfigMapStorage) DeepCopy() *DataConfigMapStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(DataConfigMapStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPersistentVolumeClaimStorage) DeepCopyInto(out *DataPersistentVolumeClaimStorage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPersistentVolumeClaimStorage.
+func (in *DataPersistentVolumeClaimStorage) DeepCopy() *DataPersistentVolumeClaimStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPersistentVolumeClaimStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataStorage) DeepCopyInto(out *DataStorage) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(DataPersistentVolumeClaimStorage)
+		**out = **in
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(DataConfigMapStorage)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataStorage.
+func (in *DataStorage) DeepCopy() *DataStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(DataStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataTransferStatus) DeepCopyInto(out *DataTransferStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataTransferStatus.
+func (in *DataTransferStatus) DeepCopy() *DataTransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataTransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseScriptRun) DeepCopyInto(out *DatabaseScriptRun) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseScriptRun.
+func (in *DatabaseScriptRun) DeepCopy() *DatabaseScriptRun {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseScriptRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
+	*out = *in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionSnapshotSpec) DeepCopyInto(out *DeletionSnapshotSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionSnapshotSpec.
+func (in *DeletionSnapshotSpec) DeepCopy() *DeletionSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventFeed) DeepCopyInto(out *EventFeed) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventFeed.
+func (in *EventFeed) DeepCopy() *EventFeed {
+	if in == nil {
+		return nil
+	}
+	out := new(EventFeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventFeed) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventFeedList) DeepCopyInto(out *EventFeedList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EventFeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventFeedList.
+func (in *EventFeedList) DeepCopy() *EventFeedList {
+	if in == nil {
+		return nil
+	}
+	out := new(EventFeedList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventFeedList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventFeedSpec) DeepCopyInto(out *EventFeedSpec) {
+	*out = *in
+	if in.FieldMapping != nil {
+		in, out := &in.FieldMapping, &out.FieldMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventFeedSpec.
+func (in *EventFeedSpec) DeepCopy() *EventFeedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventFeedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventFeedStatus) DeepCopyInto(out *EventFeedStatus) {
+	*out = *in
+	in.LastFetchTime.DeepCopyInto(&out.LastFetchTime)
+	in.LastSuccessfulFetchTime.DeepCopyInto(&out.LastSuccessfulFetchTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventFeedStatus.
+func (in *EventFeedStatus) DeepCopy() *EventFeedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventFeedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMigrationSource) DeepCopyInto(out *ImageMigrationSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMigrationSource.
+func (in *ImageMigrationSource) DeepCopy() *ImageMigrationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMigrationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Lineage) DeepCopyInto(out *Lineage) {
+	*out = *in
+	in.ClonedAt.DeepCopyInto(&out.ClonedAt)
+	if in.MaskedColumns != nil {
+		in, out := &in.MaskedColumns, &out.MaskedColumns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lineage.
+func (in *Lineage) DeepCopy() *Lineage {
+	if in == nil {
+		return nil
+	}
+	out := new(Lineage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenancePageStatus) DeepCopyInto(out *MaintenancePageStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePageStatus.
+func (in *MaintenancePageStatus) DeepCopy() *MaintenancePageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenancePageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaskingRule) DeepCopyInto(out *MaskingRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaskingRule.
+func (in *MaskingRule) DeepCopy() *MaskingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MaskingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationSource) DeepCopyInto(out *MigrationSource) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ImageMigrationSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSource.
+func (in *MigrationSource) DeepCopy() *MigrationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingAction) DeepCopyInto(out *PendingAction) {
+	*out = *in
+	in.ScheduledAt.DeepCopyInto(&out.ScheduledAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingAction.
+func (in *PendingAction) DeepCopy() *PendingAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingMigrations) DeepCopyInto(out *PendingMigrations) {
+	*out = *in
+	if in.Scripts != nil {
+		in, out := &in.Scripts, &out.Scripts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingMigrations.
+func (in *PendingMigrations) DeepCopy() *PendingMigrations {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingMigrations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileStatus) DeepCopyInto(out *ReconcileStatus) {
+	*out = *in
+	in.LastUpdate.DeepCopyInto(&out.LastUpdate)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileStatus.
+func (in *ReconcileStatus) DeepCopy() *ReconcileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionSpec) DeepCopyInto(out *RetentionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionSpec.
+func (in *RetentionSpec) DeepCopy() *RetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionStatus) DeepCopyInto(out *RetentionStatus) {
+	*out = *in
+	in.LastRunTime.DeepCopyInto(&out.LastRunTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionStatus.
+func (in *RetentionStatus) DeepCopy() *RetentionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SleepSchedule) DeepCopyInto(out *SleepSchedule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SleepSchedule.
+func (in *SleepSchedule) DeepCopy() *SleepSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(SleepSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SleepStatus) DeepCopyInto(out *SleepStatus) {
+	*out = *in
+	in.NextTransition.DeepCopyInto(&out.NextTransition)
+	if in.WakeUpUntil != nil {
+		in, out := &in.WakeUpUntil, &out.WakeUpUntil
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SleepStatus.
+func (in *SleepStatus) DeepCopy() *SleepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SleepStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,263 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this AppService to the hub version (v1alpha1)
+func (src *AppService) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.AppService)
+	if !ok {
+		return fmt.Errorf("unsupported conversion from %T to %T", src, dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	convertSpecToHub(&src.Spec, &dst.Spec)
+
+	dst.Status.ReconcileStatus = v1alpha1.ReconcileStatus{
+		Status:              src.Status.Reconcile.Status,
+		LastUpdate:          src.Status.Reconcile.LastUpdate,
+		Reason:              src.Status.Reconcile.Reason,
+		ConsecutiveFailures: src.Status.Reconcile.ConsecutiveFailures,
+	}
+	dst.Status.LastAction = src.Status.Reconcile.LastAction
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.OperatorVersion = src.Status.OperatorVersion
+	dst.Status.EventsDatabaseUpdated = src.Status.EventsDatabaseUpdated
+	dst.Status.EventsDatabaseScriptRuns = nil
+	for _, run := range src.Status.EventsDatabaseScriptRuns {
+		dst.Status.EventsDatabaseScriptRuns = append(dst.Status.EventsDatabaseScriptRuns, v1alpha1.DatabaseScriptRun(run))
+	}
+	dst.Status.Retention = src.Status.Retention
+	dst.Status.PendingMigrations = src.Status.PendingMigrations
+	dst.Status.PendingActions = src.Status.PendingActions
+	dst.Status.NextMaintenanceWindow = src.Status.NextMaintenanceWindow
+	dst.Status.Sleep = src.Status.Sleep
+	dst.Status.MaintenancePage = src.Status.MaintenancePage
+	dst.Status.Components = src.Status.Components
+	dst.Status.URLs = src.Status.URLs
+	dst.Status.Lineage = src.Status.Lineage
+	dst.Status.Conditions = src.Status.Conditions
+
+	return nil
+}
+
+// ConvertFrom converts an AppService from the hub version (v1alpha1) to this version
+func (dst *AppService) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.AppService)
+	if !ok {
+		return fmt.Errorf("unsupported conversion from %T to %T", srcRaw, dst)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	convertSpecFromHub(&src.Spec, &dst.Spec)
+
+	dst.Status.Reconcile = ReconcileStatus{
+		Status:              src.Status.Status,
+		LastUpdate:          src.Status.LastUpdate,
+		Reason:              src.Status.Reason,
+		ConsecutiveFailures: src.Status.ConsecutiveFailures,
+		LastAction:          src.Status.LastAction,
+	}
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.OperatorVersion = src.Status.OperatorVersion
+	dst.Status.EventsDatabaseUpdated = src.Status.EventsDatabaseUpdated
+	dst.Status.EventsDatabaseScriptRuns = nil
+	for _, run := range src.Status.EventsDatabaseScriptRuns {
+		dst.Status.EventsDatabaseScriptRuns = append(dst.Status.EventsDatabaseScriptRuns, DatabaseScriptRun(run))
+	}
+	dst.Status.Retention = src.Status.Retention
+	dst.Status.PendingMigrations = src.Status.PendingMigrations
+	dst.Status.PendingActions = src.Status.PendingActions
+	dst.Status.NextMaintenanceWindow = src.Status.NextMaintenanceWindow
+	dst.Status.Sleep = src.Status.Sleep
+	dst.Status.MaintenancePage = src.Status.MaintenancePage
+	dst.Status.Components = src.Status.Components
+	dst.Status.URLs = src.Status.URLs
+	dst.Status.Lineage = src.Status.Lineage
+	dst.Status.Conditions = src.Status.Conditions
+
+	return nil
+}
+
+// convertSpecToHub copies the spec field by field into the hub version
+func convertSpecToHub(src *AppServiceSpec, dst *v1alpha1.AppServiceSpec) {
+	dst.Enabled = src.Enabled
+	dst.Alias = src.Alias
+	dst.Branding = nil
+	if src.Branding != nil {
+		dst.Branding = &v1alpha1.BrandingSpec{
+			Title:        src.Branding.Title,
+			LogoURL:      src.Branding.LogoURL,
+			PrimaryColor: src.Branding.PrimaryColor,
+			AccentColor:  src.Branding.AccentColor,
+		}
+	}
+	dst.Retention = nil
+	if src.Retention != nil {
+		dst.Retention = &v1alpha1.RetentionSpec{
+			Days:     src.Retention.Days,
+			Schedule: src.Retention.Schedule,
+		}
+	}
+	dst.MigrationSources = nil
+	for _, source := range src.MigrationSources {
+		migrationSource := v1alpha1.MigrationSource{
+			Name:      source.Name,
+			ConfigMap: source.ConfigMap,
+		}
+		if source.Image != nil {
+			migrationSource.Image = &v1alpha1.ImageMigrationSource{
+				Image: source.Image.Image,
+				Path:  source.Image.Path,
+			}
+		}
+		dst.MigrationSources = append(dst.MigrationSources, migrationSource)
+	}
+	dst.Database = nil
+	if src.Database != nil {
+		dst.Database = &v1alpha1.DatabaseSpec{
+			MigrationApproval: v1alpha1.MigrationApproval(src.Database.MigrationApproval),
+			StorageClass:      src.Database.StorageClass,
+			ReadOnly:          v1alpha1.ReadOnlyMode(src.Database.ReadOnly),
+			KeepRunning:       src.Database.KeepRunning,
+		}
+		if src.Database.Storage != nil {
+			storage := src.Database.Storage.DeepCopy()
+			dst.Database.Storage = &storage
+		}
+	}
+	dst.MaintenanceWindows = nil
+	for _, window := range src.MaintenanceWindows {
+		maintenanceWindow := v1alpha1.MaintenanceWindow{
+			Schedule: window.Schedule,
+			Start:    window.Start,
+			Duration: window.Duration,
+			TimeZone: window.TimeZone,
+		}
+		for _, day := range window.Days {
+			maintenanceWindow.Days = append(maintenanceWindow.Days, v1alpha1.Weekday(day))
+		}
+		dst.MaintenanceWindows = append(dst.MaintenanceWindows, maintenanceWindow)
+	}
+	dst.Schedule = nil
+	if src.Schedule != nil {
+		dst.Schedule = &v1alpha1.SleepSchedule{
+			Sleep:    src.Schedule.Sleep,
+			Wake:     src.Schedule.Wake,
+			TimeZone: src.Schedule.TimeZone,
+		}
+	}
+	dst.DeletionPolicy = v1alpha1.DeletionPolicy(src.DeletionPolicy)
+	dst.DeletionSnapshot = nil
+	if src.DeletionSnapshot != nil {
+		dst.DeletionSnapshot = &v1alpha1.DeletionSnapshotSpec{
+			Format: v1alpha1.DataFormat(src.DeletionSnapshot.Format),
+		}
+		if claim := src.DeletionSnapshot.Storage.PersistentVolumeClaim; claim != nil {
+			dst.DeletionSnapshot.Storage.PersistentVolumeClaim = &v1alpha1.DataPersistentVolumeClaimStorage{
+				ClaimName: claim.ClaimName,
+				Path:      claim.Path,
+			}
+		}
+		if configMap := src.DeletionSnapshot.Storage.ConfigMap; configMap != nil {
+			dst.DeletionSnapshot.Storage.ConfigMap = &v1alpha1.DataConfigMapStorage{
+				Name: configMap.Name,
+				Key:  configMap.Key,
+			}
+		}
+	}
+}
+
+// convertSpecFromHub copies the spec of the hub version field by field
+func convertSpecFromHub(src *v1alpha1.AppServiceSpec, dst *AppServiceSpec) {
+	dst.Enabled = src.Enabled
+	dst.Alias = src.Alias
+	dst.Branding = nil
+	if src.Branding != nil {
+		dst.Branding = &BrandingSpec{
+			Title:        src.Branding.Title,
+			LogoURL:      src.Branding.LogoURL,
+			PrimaryColor: src.Branding.PrimaryColor,
+			AccentColor:  src.Branding.AccentColor,
+		}
+	}
+	dst.Retention = nil
+	if src.Retention != nil {
+		dst.Retention = &RetentionSpec{
+			Days:     src.Retention.Days,
+			Schedule: src.Retention.Schedule,
+		}
+	}
+	dst.MigrationSources = nil
+	for _, source := range src.MigrationSources {
+		migrationSource := MigrationSource{
+			Name:      source.Name,
+			ConfigMap: source.ConfigMap,
+		}
+		if source.Image != nil {
+			migrationSource.Image = &ImageMigrationSource{
+				Image: source.Image.Image,
+				Path:  source.Image.Path,
+			}
+		}
+		dst.MigrationSources = append(dst.MigrationSources, migrationSource)
+	}
+	dst.Database = nil
+	if src.Database != nil {
+		dst.Database = &DatabaseSpec{
+			MigrationApproval: MigrationApproval(src.Database.MigrationApproval),
+			StorageClass:      src.Database.StorageClass,
+			ReadOnly:          ReadOnlyMode(src.Database.ReadOnly),
+			KeepRunning:       src.Database.KeepRunning,
+		}
+		if src.Database.Storage != nil {
+			storage := src.Database.Storage.DeepCopy()
+			dst.Database.Storage = &storage
+		}
+	}
+	dst.MaintenanceWindows = nil
+	for _, window := range src.MaintenanceWindows {
+		maintenanceWindow := MaintenanceWindow{
+			Schedule: window.Schedule,
+			Start:    window.Start,
+			Duration: window.Duration,
+			TimeZone: window.TimeZone,
+		}
+		for _, day := range window.Days {
+			maintenanceWindow.Days = append(maintenanceWindow.Days, Weekday(day))
+		}
+		dst.MaintenanceWindows = append(dst.MaintenanceWindows, maintenanceWindow)
+	}
+	dst.Schedule = nil
+	if src.Schedule != nil {
+		dst.Schedule = &SleepSchedule{
+			Sleep:    src.Schedule.Sleep,
+			Wake:     src.Schedule.Wake,
+			TimeZone: src.Schedule.TimeZone,
+		}
+	}
+	dst.DeletionPolicy = DeletionPolicy(src.DeletionPolicy)
+	dst.DeletionSnapshot = nil
+	if src.DeletionSnapshot != nil {
+		dst.DeletionSnapshot = &DeletionSnapshotSpec{
+			Format: DataFormat(src.DeletionSnapshot.Format),
+		}
+		if claim := src.DeletionSnapshot.Storage.PersistentVolumeClaim; claim != nil {
+			dst.DeletionSnapshot.Storage.PersistentVolumeClaim = &DataPersistentVolumeClaimStorage{
+				ClaimName: claim.ClaimName,
+				Path:      claim.Path,
+			}
+		}
+		if configMap := src.DeletionSnapshot.Storage.ConfigMap; configMap != nil {
+			dst.DeletionSnapshot.Storage.ConfigMap = &DataConfigMapStorage{
+				Name: configMap.Name,
+				Key:  configMap.Key,
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHubAppService() *v1alpha1.AppService {
+	storage := resource.MustParse("1Gi")
+	return &v1alpha1.AppService{
+		ObjectMeta: metav1.ObjectMeta{Name: "gramola", Namespace: "gramola"},
+		Spec: v1alpha1.AppServiceSpec{
+			Enabled:   true,
+			Alias:     "Gramophone",
+			Branding:  &v1alpha1.BrandingSpec{Title: "Gigs", LogoURL: "https://example.com/logo.png", PrimaryColor: "#cc0000", AccentColor: "#f0ab00"},
+			Retention: &v1alpha1.RetentionSpec{Days: 30, Schedule: "0 3 * * *"},
+			MigrationSources: []v1alpha1.MigrationSource{
+				{Name: "extra", ConfigMap: "extra-scripts"},
+				{Name: "image", Image: &v1alpha1.ImageMigrationSource{Image: "quay.io/gramola/scripts:1", Path: "/scripts"}},
+			},
+			Database: &v1alpha1.DatabaseSpec{
+				MigrationApproval: v1alpha1.MigrationApprovalManual,
+				Storage:           &storage,
+				StorageClass:      "gp2",
+				ReadOnly:          v1alpha1.ReadOnlyModeDuringOperations,
+				KeepRunning:       true,
+			},
+			MaintenanceWindows: []v1alpha1.MaintenanceWindow{
+				{Days: []v1alpha1.Weekday{v1alpha1.Saturday, v1alpha1.Sunday}, Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}, TimeZone: "Europe/Madrid"},
+				{Schedule: "30 22 * * 3", Duration: metav1.Duration{Duration: 30 * time.Minute}},
+			},
+			Schedule:       &v1alpha1.SleepSchedule{Sleep: "0 20 * * 1-5", Wake: "0 8 * * 1-5", TimeZone: "UTC"},
+			DeletionPolicy: v1alpha1.DeletionPolicySnapshot,
+			DeletionSnapshot: &v1alpha1.DeletionSnapshotSpec{
+				Format:  v1alpha1.DataFormatCSV,
+				Storage: v1alpha1.DataStorage{ConfigMap: &v1alpha1.DataConfigMapStorage{Name: "final", Key: "events.csv"}},
+			},
+		},
+		Status: v1alpha1.AppServiceStatus{
+			ReconcileStatus:    v1alpha1.ReconcileStatus{Status: v1alpha1.ReconcileStatusSucceeded, Reason: "Applied", ConsecutiveFailures: 2},
+			LastAction:         v1alpha1.NoAction,
+			ObservedGeneration: 3,
+			EventsDatabaseScriptRuns: []v1alpha1.DatabaseScriptRun{
+				{Script: "events-database-update-0.0.2.sql", Status: v1alpha1.DatabaseUpdateStatusSucceeded},
+			},
+		},
+	}
+}
+
+func TestSpecFieldsAreSet(t *testing.T) {
+	// New fields must be converted, and set here so the round trip covers them
+	spec := reflect.ValueOf(newHubAppService().Spec)
+	for i := 0; i < spec.NumField(); i++ {
+		if reflect.DeepEqual(spec.Field(i).Interface(), reflect.Zero(spec.Field(i).Type()).Interface()) {
+			t.Errorf("Spec field %s is not set in the test AppService", spec.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	hub := newHubAppService()
+
+	converted := &AppService{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom returned an error: %v", err)
+	}
+	if converted.Status.Reconcile.LastAction != v1alpha1.NoAction || converted.Status.EventsDatabaseScriptRuns[0].Status != v1alpha1.DatabaseUpdateStatusSucceeded {
+		t.Errorf("ConvertFrom didn't move the status fields: %+v", converted.Status)
+	}
+
+	back := &v1alpha1.AppService{}
+	if err := converted.ConvertTo(back); err != nil {
+		t.Fatalf("ConvertTo returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(hub, back) {
+		t.Errorf("Round trip changed the AppService\nfrom %+v\nto   %+v", hub, back)
+	}
+}
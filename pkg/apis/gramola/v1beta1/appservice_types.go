@@ -0,0 +1,325 @@
+package v1beta1
+
+import (
+	"github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppServiceSpec defines the desired state of AppService
+type AppServiceSpec struct {
+	// Flags if the the AppService object is enabled or not, if not events, gateway and frontend (and the events database
+	// unless database.keepRunning is set) are scaled down to zero and restored when enabled again
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enabled"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled bool `json:"enabled"`
+
+	// Different names for Gramola Service
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Alias"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// +kubebuilder:validation:Enum=Gramola;Gramophone;Phonograph
+	Alias string `json:"alias,omitempty"`
+
+	// Overrides of the branding of the frontend, which defaults to the one of the alias
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Branding"
+	Branding *BrandingSpec `json:"branding,omitempty"`
+
+	// Retention policy to purge past events
+	// +optional
+	Retention *RetentionSpec `json:"retention,omitempty"`
+
+	// Additional migration scripts run in order after Gramola's own
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Migration Sources"
+	MigrationSources []MigrationSource `json:"migrationSources,omitempty"`
+
+	// Events database settings
+	// +optional
+	Database *DatabaseSpec `json:"database,omitempty"`
+
+	// Windows when disruptive actions (image updates, migrations, credential rotation and storage resize)
+	// can run, if empty they run as soon as they are needed
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Maintenance Windows"
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Sleep mode, the AppService is scaled down to zero from sleep to wake time
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Sleep Schedule"
+	Schedule *SleepSchedule `json:"schedule,omitempty"`
+
+	// Delete removes the events database volume with the AppService, Retain keeps the volume and its credentials and
+	// Snapshot exports the events before the AppService is deleted. Defaults to Delete
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Deletion Policy"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Delete"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Retain"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Snapshot"
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Where the events are exported with the Snapshot deletion policy, a ConfigMap named <name>-final-snapshot by default
+	// +optional
+	DeletionSnapshot *DeletionSnapshotSpec `json:"deletionSnapshot,omitempty"`
+}
+
+// MigrationApproval defines how database migrations are approved
+type MigrationApproval string
+
+// MigrationApprovals defined here
+const (
+	MigrationApprovalAutomatic MigrationApproval = "Automatic"
+	MigrationApprovalManual    MigrationApproval = "Manual"
+)
+
+// DeletionPolicy defines what happens to the events when the AppService is deleted
+type DeletionPolicy string
+
+// DeletionPolicies defined here
+const (
+	DeletionPolicyDelete   DeletionPolicy = "Delete"
+	DeletionPolicyRetain   DeletionPolicy = "Retain"
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
+// DeletionSnapshotSpec defines the final export of the events with the Snapshot deletion policy
+type DeletionSnapshotSpec struct {
+	// Format of the exported file, defaults to JSONLines
+	// +optional
+	// +kubebuilder:validation:Enum=JSONLines;CSV
+	Format DataFormat `json:"format,omitempty"`
+
+	// Where the exported file is written
+	Storage DataStorage `json:"storage"`
+}
+
+// ReadOnlyMode defines when the events database is read-only
+type ReadOnlyMode string
+
+// ReadOnlyModes defined here
+const (
+	ReadOnlyModeNever            ReadOnlyMode = "Never"
+	ReadOnlyModeDuringOperations ReadOnlyMode = "DuringOperations"
+	ReadOnlyModeAlways           ReadOnlyMode = "Always"
+)
+
+// DatabaseSpec defines the events database settings
+type DatabaseSpec struct {
+	// Automatic runs migrations as soon as they are found, Manual waits until the digest of the pending
+	// migrations is set in the gramola.redhat.com/approved-migrations annotation. Defaults to Automatic
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Migration Approval"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Automatic"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Manual"
+	MigrationApproval MigrationApproval `json:"migrationApproval,omitempty"`
+
+	// Size of the events database volume, defaults to 512Mi. It can grow if the storage class allows expansion
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Database Storage"
+	Storage *resource.Quantity `json:"storage,omitempty"`
+
+	// Storage class of the events database volume, the default one if empty. It can't change once set
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Database Storage Class"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:StorageClass"
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Never keeps the events database read-write, DuringOperations makes it read-only while backups (AppServiceDataExports)
+	// and migrations run and Always keeps it read-only. Defaults to Never
+	// +kubebuilder:validation:Enum=Never;DuringOperations;Always
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Read Only"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Never"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:DuringOperations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:select:Always"
+	ReadOnly ReadOnlyMode `json:"readOnly,omitempty"`
+
+	// Keeps the events database running while the rest of the AppService is scaled down to zero
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Keep Database Running"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	KeepRunning bool `json:"keepRunning,omitempty"`
+}
+
+// RetentionSpec defines how long past events are kept
+type RetentionSpec struct {
+	// Days events are kept after their (end) date
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Retention Days"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	Days int32 `json:"days"`
+
+	// Schedule of the purge in Cron format, defaults to every day at 03:00
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Retention Schedule"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// BrandingSpec overrides the branding of the frontend
+type BrandingSpec struct {
+	// Title shown by the frontend
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Title"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Title string `json:"title,omitempty"`
+
+	// URL of the logo shown by the frontend
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Logo URL"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	LogoURL string `json:"logoURL,omitempty"`
+
+	// Primary colour of the frontend, e.g. #cc0000
+	// +optional
+	// +kubebuilder:validation:Pattern=`^#[0-9a-fA-F]{6}$`
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Primary Colour"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	PrimaryColor string `json:"primaryColor,omitempty"`
+
+	// Accent colour of the frontend, e.g. #f0ab00
+	// +optional
+	// +kubebuilder:validation:Pattern=`^#[0-9a-fA-F]{6}$`
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Accent Colour"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	AccentColor string `json:"accentColor,omitempty"`
+}
+
+// ReconcileStatus defines the reconciliation status, it tells if the spec was applied while the Available condition
+// tells if the AppService is ready
+type ReconcileStatus struct {
+	// Status shows the reconcile run, Succeeded once the whole spec is applied, Progressing while the reconciliation
+	// waits for something (the database to be ready, a maintenance window) and Failed on errors
+	// +kubebuilder:validation:Enum=Succeeded;Progressing;Failed
+	Status v1alpha1.AppServiceConditionStatus `json:"status,omitempty"`
+
+	// LastUpdate records the last time an update was registered
+	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
+
+	// Reason for the update or change in status
+	Reason string `json:"reason,omitempty"`
+
+	// Failed reconciliations in a row, retries back off exponentially with them
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// Last Action run
+	// +kubebuilder:validation:Enum=BackupStarted;NoAction;RequeueEvent
+	LastAction v1alpha1.ActionType `json:"lastAction,omitempty"`
+}
+
+// DatabaseScriptRun logs script run and status
+type DatabaseScriptRun struct {
+	// Script
+	Script string `json:"script"`
+
+	// Status of the run of the Script
+	// +kubebuilder:validation:Enum=Succeeded;Failed;Unknown
+	Status v1alpha1.DatabaseUpdateStatus `json:"status,omitempty"`
+
+	// Error rendering or running the Script
+	Message string `json:"message,omitempty"`
+}
+
+// AppServiceStatus defines the observed state of AppService
+type AppServiceStatus struct {
+	// Result of the last reconciliation
+	// +optional
+	Reconcile ReconcileStatus `json:"reconcile,omitempty"`
+
+	// Generation of the spec last applied, it equals metadata.generation once the reconciliation Succeeded
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Version of the operator that last reconciled the AppService, older operators can't take it over
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// Indicates if the Events Database has been updated or not
+	// +kubebuilder:validation:Enum=Succeeded;Failed;Unknown
+	EventsDatabaseUpdated v1alpha1.DatabaseUpdateStatus `json:"eventsDatabaseUpdated,omitempty"`
+
+	// List of Event Database Scripts Runs
+	EventsDatabaseScriptRuns []DatabaseScriptRun `json:"eventsDatabaseScriptRuns,omitempty"`
+
+	// Result of the last purge of past events
+	Retention *v1alpha1.RetentionStatus `json:"retention,omitempty"`
+
+	// Migrations waiting for approval
+	PendingMigrations *v1alpha1.PendingMigrations `json:"pendingMigrations,omitempty"`
+
+	// Disruptive actions waiting for a maintenance window
+	PendingActions []v1alpha1.PendingAction `json:"pendingActions,omitempty"`
+
+	// Start of the next maintenance window, the current one if open
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+
+	// Sleep state, if a sleep schedule is set
+	Sleep *v1alpha1.SleepStatus `json:"sleep,omitempty"`
+
+	// Last window the frontend was replaced by the maintenance page during a migration or restore
+	MaintenancePage *v1alpha1.MaintenancePageStatus `json:"maintenancePage,omitempty"`
+
+	// Health of each component
+	Components []v1alpha1.ComponentStatus `json:"components,omitempty"`
+
+	// URLs of the application, from the hosts admitted for its Routes
+	// +optional
+	URLs *v1alpha1.AppServiceURLs `json:"urls,omitempty"`
+
+	// Source of the events if they were cloned from another AppService
+	Lineage *v1alpha1.Lineage `json:"lineage,omitempty"`
+
+	// Status Conditions
+	Conditions []v1alpha1.AppServiceCondition `json:"conditions,omitempty"` // Used to wait => oc wait appservice/gramola --for=condition=Available
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppService is the Schema for the appservices API defines Gramola Backend Services
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="AppService"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=appservices,scope=Namespaced
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type==\"Available\")].status"
+// +kubebuilder:printcolumn:name="Events",type="integer",JSONPath=".status.components[?(@.name==\"events\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Database",type="integer",JSONPath=".status.components[?(@.name==\"events-database\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Gateway",type="integer",JSONPath=".status.components[?(@.name==\"gateway\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Frontend",type="integer",JSONPath=".status.components[?(@.name==\"frontend\")].readyReplicas"
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".status.components[?(@.name==\"frontend\")].routeHost"
+// +kubebuilder:printcolumn:name="Warnings",type="string",JSONPath=".status.components[*].lastWarning",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type AppService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppServiceSpec   `json:"spec,omitempty"`
+	Status AppServiceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppServiceList contains a list of AppService
+type AppServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppService `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppService{}, &AppServiceList{})
+}
@@ -0,0 +1,38 @@
+package v1beta1
+
+// DataFormat defines the potential formats of exported event data
+type DataFormat string
+
+// DataFormats defined here
+const (
+	DataFormatJSONLines DataFormat = "JSONLines"
+	DataFormatCSV       DataFormat = "CSV"
+)
+
+// DataPersistentVolumeClaimStorage locates a file in a PersistentVolumeClaim
+type DataPersistentVolumeClaimStorage struct {
+	// Name of the PersistentVolumeClaim in the same namespace
+	ClaimName string `json:"claimName"`
+	// Path of the file relative to the root of the volume, defaults to events.jsonl or events.csv
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// DataConfigMapStorage locates a key in a ConfigMap
+type DataConfigMapStorage struct {
+	// Name of the ConfigMap in the same namespace
+	Name string `json:"name"`
+	// Key holding the data, defaults to events.jsonl or events.csv
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DataStorage defines where event data is stored, only one of the fields should be set
+type DataStorage struct {
+	// File in a PersistentVolumeClaim, preferred for big catalogues
+	// +optional
+	PersistentVolumeClaim *DataPersistentVolumeClaimStorage `json:"persistentVolumeClaim,omitempty"`
+	// Key in a ConfigMap, limited to 1MiB of data
+	// +optional
+	ConfigMap *DataConfigMapStorage `json:"configMap,omitempty"`
+}
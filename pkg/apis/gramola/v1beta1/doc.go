@@ -0,0 +1,4 @@
+// Package v1beta1 contains API Schema definitions for the gramola v1beta1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=gramola.redhat.com
+package v1beta1
@@ -0,0 +1,28 @@
+package v1beta1
+
+// MigrationSource locates additional SQL scripts run against the events database after Gramola's own,
+// exactly one of ConfigMap and Image must be set
+type MigrationSource struct {
+	// Name of the source, its scripts are tracked as <name>.<script>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+
+	// Name of a ConfigMap in the namespace, every key ending in .sql is a script
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// OCI image holding the scripts
+	// +optional
+	Image *ImageMigrationSource `json:"image,omitempty"`
+}
+
+// ImageMigrationSource is an OCI image holding .sql files, it must provide sh and cp to copy them out
+type ImageMigrationSource struct {
+	// Image reference
+	Image string `json:"image"`
+
+	// Absolute path of the directory holding the scripts, defaults to /migrations
+	// +optional
+	Path string `json:"path,omitempty"`
+}
@@ -1,23 +1,23 @@
 package appservice
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"math"
-	"regexp"
 	"strings"
 	"time"
 
 	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
 	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	_validation "github.com/redhat/gramola-operator/pkg/validation"
+	version "github.com/redhat/gramola-operator/version"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -29,17 +29,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"k8s.io/client-go/kubernetes/scheme"
-	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/client-go/tools/remotecommand"
 
 	// Route
 	routev1 "github.com/openshift/api/route/v1"
 
-	errors "github.com/pkg/errors"
-
 	// For now... blank
 	_ "github.com/redhat/gramola-operator/pkg/util"
 )
@@ -51,7 +45,6 @@ const operatorName = "gramola-operator"
 const controllerName = "controller-appservice"
 
 const (
-	errorAlias                    = "Not a proper AppService object because Alias is not Gramola, Gramophone or Phonograph"
 	errorNotAppServiceObject      = "Not a AppService object"
 	errorAppServiceObjectNotValid = "Not a valid AppService object"
 	errorUnableToUpdateInstance   = "Unable to update instance"
@@ -101,15 +94,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				log.Error(nil, "Update event has no old proper runtime object to update", "event", e)
 				return false
 			}
-			newServiceConfig, ok := e.ObjectNew.(*gramolav1alpha1.AppService)
-			if !ok {
+			// Disabled objects are reconciled too, they are scaled down to zero
+			if _, ok := e.ObjectNew.(*gramolav1alpha1.AppService); !ok {
 				log.Error(nil, "Update event has no proper new runtime object for update", "event", e)
 				return false
 			}
-			if !newServiceConfig.Spec.Enabled {
-				log.Error(nil, "Runtime object is not enabled", "event", e)
-				return false
-			}
 
 			// Also check if no change in ResourceGeneration to return false
 			if e.MetaOld == nil {
@@ -120,7 +109,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				log.Error(nil, "Update event has no new metadata", "event", e)
 				return false
 			}
-			if e.MetaNew.GetGeneration() == e.MetaOld.GetGeneration() {
+			// Approving migrations and waking up only change an annotation, deletion only sets the deletion timestamp
+			if e.MetaNew.GetGeneration() == e.MetaOld.GetGeneration() && e.MetaNew.GetDeletionTimestamp() == nil &&
+				e.MetaNew.GetAnnotations()[gramolav1alpha1.MigrationApprovalAnnotation] == e.MetaOld.GetAnnotations()[gramolav1alpha1.MigrationApprovalAnnotation] &&
+				e.MetaNew.GetAnnotations()[gramolav1alpha1.WakeUpUntilAnnotation] == e.MetaOld.GetAnnotations()[gramolav1alpha1.WakeUpUntilAnnotation] {
 				return false
 			}
 
@@ -147,6 +139,13 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			log.Info("Pod (predicate->UpdateEvent) " + e.MetaNew.GetName())
 
+			// Warnings of any component are rolled up in status
+			if oldPod, ok := e.ObjectOld.(*corev1.Pod); ok {
+				if newPod, ok := e.ObjectNew.(*corev1.Pod); ok && waitingReasons(oldPod) != waitingReasons(newPod) {
+					return true
+				}
+			}
+
 			// Ignore if not events-database-*
 			if !strings.Contains(e.MetaNew.GetName(), "events-database") {
 				log.Info("Pod is not events-database - [IGNORED]")
@@ -181,11 +180,27 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		},
 	}
 
-	// TODO(user): Modify this to be the types you create that are owned by the primary resource
-	// Watch for changes to secondary resource Pods and requeue the owner AppService
-	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
-		IsController: true,
-		OwnerType:    &gramolav1alpha1.AppService{},
+	// Watch for changes to Pods and requeue their AppService, pods are owned by ReplicaSets so they're mapped by labels
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			podLabels := a.Meta.GetLabels()
+			if podLabels["app"] != _deployment.AppName {
+				return nil
+			}
+			appServices := &gramolav1alpha1.AppServiceList{}
+			if err := mgr.GetClient().List(context.TODO(), appServices, client.InNamespace(a.Meta.GetNamespace())); err != nil {
+				log.Error(err, "Unable to list AppServices", "namespace", a.Meta.GetNamespace())
+				return nil
+			}
+			requests := []reconcile.Request{}
+			for i := range appServices.Items {
+				appService := &appServices.Items[i]
+				if _deployment.GetAppServiceSelector(appService, podLabels["component"])["app.kubernetes.io/instance"] == podLabels["app.kubernetes.io/instance"] {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: appService.Name, Namespace: appService.Namespace}})
+				}
+			}
+			return requests
+		}),
 	}, podPredicate)
 	if err != nil {
 		return err
@@ -201,6 +216,62 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to secondary resource Routes, the hosts admitted are published in status
+	err = c.Watch(&source.Kind{Type: &routev1.Route{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &gramolav1alpha1.AppService{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource CronJobs, purge Jobs that finish update their status
+	err = c.Watch(&source.Kind{Type: &batchv1beta1.CronJob{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &gramolav1alpha1.AppService{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to AppServiceDataExports, backups may make the events database read-only while they run
+	err = c.Watch(&source.Kind{Type: &gramolav1alpha1.AppServiceDataExport{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			export, ok := a.Object.(*gramolav1alpha1.AppServiceDataExport)
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: export.Spec.AppService, Namespace: export.Namespace}}}
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ConfigMaps used as migration sources and requeue the AppServices referencing them
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			appServices := &gramolav1alpha1.AppServiceList{}
+			if err := mgr.GetClient().List(context.TODO(), appServices, client.InNamespace(a.Meta.GetNamespace())); err != nil {
+				log.Error(err, "Unable to list AppServices", "namespace", a.Meta.GetNamespace())
+				return nil
+			}
+			requests := []reconcile.Request{}
+			for _, appService := range appServices.Items {
+				for _, migrationSource := range appService.Spec.MigrationSources {
+					if migrationSource.ConfigMap == a.Meta.GetName() {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: appService.Name, Namespace: appService.Namespace}})
+						break
+					}
+				}
+			}
+			return requests
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -242,23 +313,39 @@ func (r *ReconcileAppService) Reconcile(request reconcile.Request) (reconcile.Re
 		return reconcile.Result{}, err
 	}
 
+	// Objects of AppServices reconciled before they were prefixed by the AppService name keep their names, which
+	// finalizing the AppService needs too
+	if err := r.reconcileNaming(instance); err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	// Being deleted, even if not valid
+	if instance.DeletionTimestamp != nil {
+		return r.finalize(instance)
+	}
+
 	// Validate the CR instance
 	if ok, err := r.isValid(instance); !ok {
 		return r.ManageError(instance, err)
 	}
 
-	// Now that we have a target let's initialize the CR instance. Updates Spec.Initilized in `instance`
-	if initialized, err := r.isInitialized(instance); err == nil && !initialized {
-		err := r.client.Update(context.TODO(), instance)
-		if err != nil {
-			log.Error(err, errorUnableToUpdateInstance, "instance", instance)
-			return r.ManageError(instance, err)
-		}
-		return reconcile.Result{}, nil
-	} else {
-		if err != nil {
-			return r.ManageError(instance, err)
-		}
+	// The finalizer is added with a patch of the metadata only
+	if err := r.addFinalizer(instance); err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	//////////////////////////
+	// Maintenance Windows
+	//////////////////////////
+	if err := r.reconcileMaintenance(instance); err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	//////////////////////////
+	// Sleep
+	//////////////////////////
+	if err := r.reconcileSleep(instance); err != nil {
+		return r.ManageError(instance, err)
 	}
 
 	//////////////////////////
@@ -282,41 +369,57 @@ func (r *ReconcileAppService) Reconcile(request reconcile.Request) (reconcile.Re
 		return r.ManageError(instance, err)
 	}
 
+	//////////////////////////
+	// Retention
+	//////////////////////////
+	if _, err := r.reconcileRetention(instance); err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	// Nothing runs against the events database while it's scaled down to zero
+	if isScaledDown(instance, _deployment.EventsDatabaseServiceName) {
+		return r.ManageSuccess(instance, sleepRequeueAfter(instance), gramolav1alpha1.NoAction, scaledDownReason(instance))
+	}
+
+	//////////////////////////
+	// Read Only
+	//////////////////////////
+	if err := r.reconcileReadOnly(instance); err != nil {
+		return r.ManageError(instance, err)
+	}
+
 	//////////////////////////
 	// Update Events DataBase
 	//////////////////////////
-	// Update only if not applied before with success
-	if !r.CurrentDatabaseScriptWasRun(instance) {
-		// TODO Backup DB
-
-		// Start the Script Run
-		scriptRun := &gramolav1alpha1.DatabaseScriptRun{
-			Script: _deployment.EventsDatabaseUpdateScriptName,
-			Status: gramolav1alpha1.DatabaseUpdateStatusUnknown,
-		}
-		if dataBaseUpdated, err := r.UpdateEventsDatabase(request); err != nil {
-			log.Error(err, "Error DB update", "instance", instance)
-			// Update Status
-			scriptRun.Status = gramolav1alpha1.DatabaseUpdateStatusFailed
-			instance.Status.EventsDatabaseScriptRuns = append(instance.Status.EventsDatabaseScriptRuns, *scriptRun)
-			instance.Status.EventsDatabaseUpdated = gramolav1alpha1.DatabaseUpdateStatusFailed
-			return r.ManageError(instance, err)
-		} else {
-			if dataBaseUpdated {
-				log.Info(fmt.Sprintf("dataBaseUpdated ====> %s", instance.Status))
-				// Update Status
-				scriptRun.Status = gramolav1alpha1.DatabaseUpdateStatusSucceeded
-				instance.Status.EventsDatabaseScriptRuns = append(instance.Status.EventsDatabaseScriptRuns, *scriptRun)
-				instance.Status.EventsDatabaseUpdated = gramolav1alpha1.DatabaseUpdateStatusSucceeded
-			} else {
-				// Maybe the Database Pods weren't ready but running... so scchedule a new reconcile cycle
-				return r.ManageSuccess(instance, 10*time.Second, gramolav1alpha1.RequeueEvent)
-			}
+	// Run the migrations not applied before with success
+	result, err := r.reconcileMigrations(instance)
+	switch result {
+	case migrationsFailed:
+		log.Error(err, "Error DB update", "instance", instance)
+		return r.ManageError(instance, err)
+	case migrationsWaitingForDatabase:
+		// Maybe the Database Pods weren't ready but running... so scchedule a new reconcile cycle
+		return r.ManageSuccess(instance, 10*time.Second, gramolav1alpha1.RequeueEvent, "Waiting for the events database to be ready")
+	case migrationsWaitingForRollout:
+		// The events database Deployment rolls out the init containers of image migration sources, in a maintenance window if any
+		return r.ManageSuccess(instance, time.Minute, gramolav1alpha1.RequeueEvent, "Waiting for the events database to roll out its migration sources")
+	case migrationsWaitingForApproval:
+		// The spec is not applied until the migrations are approved, approving them triggers a new reconcile cycle
+		return r.ManageSuccess(instance, time.Minute, gramolav1alpha1.RequeueEvent,
+			fmt.Sprintf("Waiting for the approval of migrations %s", instance.Status.PendingMigrations.Digest))
+	}
+
+	// Come back when the window of the pending actions opens
+	if requeueAfter := maintenanceRequeueAfter(instance); requeueAfter > 0 {
+		if sleepAfter := sleepRequeueAfter(instance); sleepAfter > 0 && sleepAfter < requeueAfter {
+			requeueAfter = sleepAfter
 		}
+		return r.ManageSuccess(instance, requeueAfter, gramolav1alpha1.RequeueEvent,
+			fmt.Sprintf("Waiting for the maintenance window at %s", instance.Status.PendingActions[0].ScheduledAt.Format(time.RFC3339)))
 	}
 
-	// Nothing else to do
-	return r.ManageSuccess(instance, 0, gramolav1alpha1.NoAction)
+	// Nothing else to do, until the AppService goes to sleep or wakes up if it has a schedule
+	return r.ManageSuccess(instance, sleepRequeueAfter(instance), gramolav1alpha1.NoAction, "")
 }
 
 // isValid checks if our CR is valid or not
@@ -330,33 +433,21 @@ func (r *ReconcileAppService) isValid(obj metav1.Object) (bool, error) {
 		return false, err
 	}
 
-	// Check Alias
-	if len(instance.Spec.Alias) > 0 &&
-		instance.Spec.Alias != "Gramola" && instance.Spec.Alias != "Gramophone" && instance.Spec.Alias != "Phonograph" {
-		err := k8s_errors.NewBadRequest(errorAlias)
-		log.Error(err, errorAlias)
+	// Same checks as the validating webhook, which may not be deployed, e.g. if the operator runs locally
+	if err := _validation.ValidateAppService(instance); err != nil {
+		err = k8s_errors.NewBadRequest(err.Error())
+		log.Error(err, errorAppServiceObjectNotValid)
 		return false, err
 	}
 
-	return true, nil
-}
-
-// IsInitialized checks if our CR has been initialized or not
-func (r *ReconcileAppService) isInitialized(obj metav1.Object) (bool, error) {
-	instance, ok := obj.(*gramolav1alpha1.AppService)
-	if !ok {
-		err := k8s_errors.NewBadRequest(errorNotAppServiceObject)
-		log.Error(err, errorNotAppServiceObject)
+	// This operator can't take over an AppService reconciled by a newer one
+	if err := _validation.ValidateOperatorVersion(instance); err != nil {
+		err = k8s_errors.NewBadRequest(err.Error())
+		log.Error(err, errorAppServiceObjectNotValid)
 		return false, err
 	}
-	if instance.Spec.Initialized {
-		return true, nil
-	}
 
-	// TODO add a Finalizer...
-	// util.AddFinalizer(mycrd, controllerName)
-	instance.Spec.Initialized = true
-	return false, nil
+	return true, nil
 }
 
 // ManageError manages an error object, an instance of the CR is passed along
@@ -369,17 +460,18 @@ func (r *ReconcileAppService) ManageError(obj metav1.Object, issue error) (recon
 		r.recorder.Event(runtimeObj, "Error", "ProcessingError", err.Error())
 		return reconcile.Result{}, nil
 	}
-	var retryInterval time.Duration
+	retryInterval := backoff(1)
 	r.recorder.Event(runtimeObj, "Warning", "ProcessingError", issue.Error())
 	if instance, ok := (obj).(*gramolav1alpha1.AppService); ok {
-		lastUpdate := instance.Status.LastUpdate
-		lastStatus := instance.Status.Status
 		status := gramolav1alpha1.ReconcileStatus{
-			LastUpdate: metav1.Now(),
-			Reason:     issue.Error(),
-			Status:     gramolav1alpha1.AppServiceConditionStatusFailed,
+			LastUpdate:          metav1.Now(),
+			Reason:              issue.Error(),
+			Status:              gramolav1alpha1.ReconcileStatusFailed,
+			ConsecutiveFailures: instance.Status.ConsecutiveFailures + 1,
 		}
 		instance.Status.ReconcileStatus = status
+		r.reconcileComponents(instance)
+		r.reconcileConditions(instance, issue)
 		err := r.client.Status().Update(context.Background(), runtimeObj)
 		if err != nil {
 			log.Error(err, errorUnableToUpdateStatus)
@@ -388,23 +480,32 @@ func (r *ReconcileAppService) ManageError(obj metav1.Object, issue error) (recon
 				Requeue:      true,
 			}, nil
 		}
-		if lastUpdate.IsZero() || lastStatus == "Success" {
-			retryInterval = time.Second
-		} else {
-			retryInterval = status.LastUpdate.Sub(lastUpdate.Time.Round(time.Second))
-		}
+		retryInterval = backoff(status.ConsecutiveFailures)
 	} else {
 		log.Info("object is not RecocileStatusAware, not setting status")
-		retryInterval = time.Second
 	}
 	return reconcile.Result{
-		RequeueAfter: time.Duration(math.Min(float64(retryInterval.Nanoseconds()*2), float64(time.Hour.Nanoseconds()*6))),
+		RequeueAfter: retryInterval,
 		Requeue:      true,
 	}, nil
 }
 
-// ManageSuccess manages a success and updates status accordingly, an instance of the CR is passed along
-func (r *ReconcileAppService) ManageSuccess(obj metav1.Object, requeueAfter time.Duration, action gramolav1alpha1.ActionType) (reconcile.Result, error) {
+// backoff returns how long to wait before retrying after a number of failures in a row, it doubles from 2 seconds up to 6 hours
+func backoff(failures int32) time.Duration {
+	maxRetryInterval := 6 * time.Hour
+	if failures > 20 {
+		return maxRetryInterval
+	}
+	retryInterval := time.Second << uint(failures)
+	if retryInterval > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return retryInterval
+}
+
+// ManageSuccess manages a success and updates status accordingly, an instance of the CR is passed along. The spec is applied,
+// and its generation observed, only with NoAction, otherwise the reconciliation is progressing for reason
+func (r *ReconcileAppService) ManageSuccess(obj metav1.Object, requeueAfter time.Duration, action gramolav1alpha1.ActionType, reason string) (reconcile.Result, error) {
 	log.Info(fmt.Sprintf("===> ManageSuccess with requeueAfter: %d from: %s", requeueAfter, action))
 	runtimeObj, ok := (obj).(runtime.Object)
 	if !ok {
@@ -414,11 +515,18 @@ func (r *ReconcileAppService) ManageSuccess(obj metav1.Object, requeueAfter time
 	if instance, ok := (obj).(*gramolav1alpha1.AppService); ok {
 		status := gramolav1alpha1.ReconcileStatus{
 			LastUpdate: metav1.Now(),
-			Reason:     "",
-			Status:     gramolav1alpha1.AppServiceConditionStatusTrue,
+			Reason:     reason,
+			Status:     gramolav1alpha1.ReconcileStatusProgressing,
+		}
+		if action == gramolav1alpha1.NoAction {
+			status.Status = gramolav1alpha1.ReconcileStatusSucceeded
+			instance.Status.ObservedGeneration = instance.Generation
 		}
 		instance.Status.ReconcileStatus = status
 		instance.Status.LastAction = action
+		instance.Status.OperatorVersion = version.Version
+		r.reconcileComponents(instance)
+		r.reconcileConditions(instance, nil)
 
 		err := r.client.Status().Update(context.Background(), runtimeObj)
 		if err != nil {
@@ -445,108 +553,3 @@ func (r *ReconcileAppService) ManageSuccess(obj metav1.Object, requeueAfter time
 	}
 	return reconcile.Result{}, nil
 }
-
-// UpdateEventsDatabase runs a script in the first 'Events' database pod found (and ready) returns true if the script was run succesfully
-func (r *ReconcileAppService) UpdateEventsDatabase(request reconcile.Request) (bool, error) {
-	// List all pods of the Events Database
-	podList := &corev1.PodList{}
-	lbs := map[string]string{
-		"component": _deployment.EventsDatabaseServiceName,
-	}
-	labelSelector := labels.SelectorFromSet(lbs)
-	listOps := &client.ListOptions{Namespace: request.Namespace, LabelSelector: labelSelector}
-	if err := r.client.List(context.TODO(), podList, listOps); err != nil {
-		return false, err
-	}
-
-	// Count the pods that are pending or running as available
-	var ready []corev1.Pod
-	for _, pod := range podList.Items {
-		log.Info(fmt.Sprintf("pod: %s phase: %s statuses: %s", pod.Name, pod.Status.Phase, pod.Status.ContainerStatuses))
-		if pod.Status.Phase == corev1.PodRunning {
-			for _, containerStatus := range pod.Status.ContainerStatuses {
-				if containerStatus.Name == _deployment.EventsDatabaseServiceContainerName && containerStatus.Ready {
-					ready = append(ready, pod)
-					break
-				}
-			}
-		}
-	}
-
-	log.Info(fmt.Sprintf("ready: %s", ready))
-
-	if len(ready) > 0 {
-		filePath := _deployment.EventsDatabaseScriptsMountPath + "/" + _deployment.EventsDatabaseUpdateScriptName
-		if _out, _err, err := r.ExecuteRemoteCommand(&ready[0], "psql -U $POSTGRESQL_USER $POSTGRESQL_DATABASE -f "+filePath); err != nil {
-			return false, err
-		} else {
-			log.Info(fmt.Sprintf("stdout: %s\nstderr: %s", _out, _err))
-			if len(_err) > 0 {
-				return false, errors.Wrapf(err, "Failed executing script %s on %s", filePath, _deployment.EventsDatabaseServiceName)
-			} else {
-				errorFound := regexp.MustCompile(`(?i)error`)
-				if errorFound.MatchString(_out) {
-					return false, errors.Wrapf(err, "Failed executing script %s on %s", filePath, _deployment.EventsDatabaseServiceName)
-				}
-				return true, nil
-			}
-		}
-	}
-
-	return false, nil
-}
-
-// ExecuteRemoteCommand executes a remote shell command on the given pod
-// returns the output from stdout and stderr
-func (r *ReconcileAppService) ExecuteRemoteCommand(pod *corev1.Pod, command string) (string, string, error) {
-	kubeCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{},
-	)
-	restCfg, err := kubeCfg.ClientConfig()
-	if err != nil {
-		return "", "", err
-	}
-	coreClient, err := corev1client.NewForConfig(restCfg)
-	if err != nil {
-		return "", "", err
-	}
-
-	buf := &bytes.Buffer{}
-	errBuf := &bytes.Buffer{}
-	request := coreClient.RESTClient().
-		Post().
-		Namespace(pod.Namespace).
-		Resource("pods").
-		Name(pod.Name).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Command: []string{"/bin/bash", "-c", command},
-			Stdin:   false,
-			Stdout:  true,
-			Stderr:  true,
-			TTY:     true,
-		}, scheme.ParameterCodec)
-	exec, err := remotecommand.NewSPDYExecutor(restCfg, "POST", request.URL())
-	err = exec.Stream(remotecommand.StreamOptions{
-		Stdout: buf,
-		Stderr: errBuf,
-	})
-	if err != nil {
-		return "", "", errors.Wrapf(err, "Failed executing command %s on %v/%v", command, pod.Namespace, pod.Name)
-	}
-
-	return buf.String(), errBuf.String(), nil
-}
-
-// CurrentDatabaseScriptWasRun checks if the current Database Update Script was run
-func (r *ReconcileAppService) CurrentDatabaseScriptWasRun(instance *gramolav1alpha1.AppService) bool {
-	for i := range instance.Status.EventsDatabaseScriptRuns {
-		if instance.Status.EventsDatabaseScriptRuns[i].Script == _deployment.EventsDatabaseUpdateScriptName &&
-			instance.Status.EventsDatabaseScriptRuns[i].Status == gramolav1alpha1.DatabaseUpdateStatusSucceeded {
-			return true
-		}
-	}
-
-	return false
-}
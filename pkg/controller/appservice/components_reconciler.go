@@ -0,0 +1,129 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Waiting reasons of containers that are part of a normal start
+var startingReasons = map[string]bool{
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// Reconciling Components, the health of each Deployment, its Route and its pods is rolled up in status, as well as the URLs of the Routes
+func (r *ReconcileAppService) reconcileComponents(instance *gramolav1alpha1.AppService) {
+	components := []gramolav1alpha1.ComponentStatus{}
+	urls := map[string]string{}
+	for _, name := range appServiceDeployments {
+		component := gramolav1alpha1.ComponentStatus{Name: name}
+
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, name), Namespace: instance.Namespace}, deployment); err == nil {
+			component.DesiredReplicas = 1
+			if deployment.Spec.Replicas != nil {
+				component.DesiredReplicas = *deployment.Spec.Replicas
+			}
+			component.ReadyReplicas = deployment.Status.ReadyReplicas
+			if len(deployment.Spec.Template.Spec.Containers) > 0 {
+				component.Image = deployment.Spec.Template.Spec.Containers[0].Image
+			}
+		}
+
+		route := &routev1.Route{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, name), Namespace: instance.Namespace}, route); err == nil {
+			component.RouteHost = getAdmittedHost(route)
+			if len(component.RouteHost) > 0 {
+				urls[name] = getRouteURL(route, component.RouteHost)
+			}
+		}
+
+		warning, err := r.getComponentWarning(instance, name)
+		if err != nil {
+			log.Error(err, "Unable to list pods", "component", name)
+		}
+		component.LastWarning = warning
+
+		components = append(components, component)
+	}
+	instance.Status.Components = components
+
+	instance.Status.URLs = nil
+	if len(urls) > 0 {
+		instance.Status.URLs = &gramolav1alpha1.AppServiceURLs{
+			Frontend: urls[_deployment.FrontendServiceName],
+			Gateway:  urls[_deployment.GatewayServiceName],
+			Events:   urls[_deployment.EventsServiceName],
+		}
+	}
+}
+
+// getComponentWarning returns the last waiting reason, not part of a normal start, of the containers of the pods of a component
+func (r *ReconcileAppService) getComponentWarning(instance *gramolav1alpha1.AppService, name string) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.client.List(context.TODO(), pods, client.InNamespace(instance.Namespace), client.MatchingLabels(_deployment.GetAppServiceSelector(instance, name))); err != nil {
+		return "", err
+	}
+	warning := ""
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		for _, status := range containerStatuses(pod) {
+			if status.State.Waiting != nil && len(status.State.Waiting.Reason) > 0 && !startingReasons[status.State.Waiting.Reason] {
+				warning = fmt.Sprintf("%s/%s: %s", pod.Name, status.Name, status.State.Waiting.Reason)
+				if len(status.State.Waiting.Message) > 0 {
+					warning += ": " + status.State.Waiting.Message
+				}
+			}
+		}
+	}
+	return warning, nil
+}
+
+// getAdmittedHost returns the host a router admitted for a Route, the requested host if none did yet
+func getAdmittedHost(route *routev1.Route) string {
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionTrue {
+				return ingress.Host
+			}
+		}
+	}
+	return route.Spec.Host
+}
+
+// getRouteURL returns the URL of a Route given its host, https if it terminates TLS
+func getRouteURL(route *routev1.Route, host string) string {
+	if route.Spec.TLS != nil {
+		return "https://" + host
+	}
+	return "http://" + host
+}
+
+// waitingReasons returns the waiting reasons of the containers of a pod
+func waitingReasons(pod *corev1.Pod) string {
+	reasons := ""
+	for _, status := range containerStatuses(pod) {
+		if status.State.Waiting != nil {
+			reasons += status.Name + "=" + status.State.Waiting.Reason + ";"
+		}
+	}
+	return reasons
+}
+
+// containerStatuses returns the statuses of the init containers and containers of a pod, pods come from the cache
+// so their slices are not appended to
+func containerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
@@ -0,0 +1,163 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	version "github.com/redhat/gramola-operator/version"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Deployments of an AppService, in the order they are reported
+var appServiceDeployments = []string{
+	_deployment.EventsDatabaseServiceName,
+	_deployment.EventsServiceName,
+	_deployment.GatewayServiceName,
+	_deployment.FrontendServiceName,
+}
+
+// Reconciling Conditions, Available, Progressing, Degraded and Promoted are computed from the Deployments, migrations,
+// backups and the error of the reconciliation if any. Only status changes move lastTransitionTime
+func (r *ReconcileAppService) reconcileConditions(instance *gramolav1alpha1.AppService, issue error) {
+	unavailable, rolling, stalled, outdated := []string{}, []string{}, []string{}, []string{}
+	for _, name := range appServiceDeployments {
+		deployment := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, name), Namespace: instance.Namespace}, deployment); err != nil {
+			unavailable = append(unavailable, name)
+			continue
+		}
+		if !isDeploymentAvailable(deployment) {
+			unavailable = append(unavailable, name)
+		}
+		if isDeploymentRolling(deployment) {
+			rolling = append(rolling, name)
+		}
+		if isDeploymentStalled(deployment) {
+			stalled = append(stalled, name)
+		}
+		if deployment.Labels["version"] != version.Version {
+			outdated = append(outdated, name)
+		}
+	}
+
+	backups, err := r.getRunningBackups(instance)
+	if err != nil {
+		log.Error(err, "Unable to list backups")
+	}
+
+	// Available
+	if reason := scaledDownReason(instance); len(reason) > 0 {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeAvailable, gramolav1alpha1.AppServiceConditionStatusFalse,
+			gramolav1alpha1.AppServiceConditionReasonWaiting, fmt.Sprintf("Scaled down to zero: %s", reason))
+	} else if len(unavailable) == 0 {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeAvailable, gramolav1alpha1.AppServiceConditionStatusTrue,
+			gramolav1alpha1.AppServiceConditionReasonSucceeded, "All components are available")
+	} else {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeAvailable, gramolav1alpha1.AppServiceConditionStatusFalse,
+			gramolav1alpha1.AppServiceConditionReasonWaiting, fmt.Sprintf("Components not available: %s", strings.Join(unavailable, ", ")))
+	}
+
+	// Progressing
+	progressing := []string{}
+	if len(rolling) > 0 {
+		progressing = append(progressing, fmt.Sprintf("Rolling out %s", strings.Join(rolling, ", ")))
+	}
+	if len(unavailable) > 0 && len(rolling) == 0 {
+		progressing = append(progressing, fmt.Sprintf("Waiting for %s", strings.Join(unavailable, ", ")))
+	}
+	if len(backups) > 0 {
+		progressing = append(progressing, fmt.Sprintf("Running backups %s", strings.Join(backups, ", ")))
+	}
+	if pending := instance.Status.PendingMigrations; pending != nil {
+		progressing = append(progressing, fmt.Sprintf("Migrations %s wait for approval", strings.Join(pending.Scripts, ", ")))
+	}
+	if len(progressing) > 0 {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeProgressing, gramolav1alpha1.AppServiceConditionStatusTrue,
+			gramolav1alpha1.AppServiceConditionReasonProgressing, strings.Join(progressing, "; "))
+	} else {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeProgressing, gramolav1alpha1.AppServiceConditionStatusFalse,
+			gramolav1alpha1.AppServiceConditionReasonSucceeded, "Nothing in progress")
+	}
+
+	// Degraded
+	degraded := []string{}
+	if issue != nil {
+		degraded = append(degraded, issue.Error())
+	}
+	if instance.Status.EventsDatabaseUpdated == gramolav1alpha1.DatabaseUpdateStatusFailed {
+		degraded = append(degraded, "Migrations failed")
+	}
+	if len(stalled) > 0 {
+		degraded = append(degraded, fmt.Sprintf("Rollout of %s exceeded its progress deadline", strings.Join(stalled, ", ")))
+	}
+	if len(degraded) > 0 {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeDegraded, gramolav1alpha1.AppServiceConditionStatusTrue,
+			gramolav1alpha1.AppServiceConditionReasonFailed, strings.Join(degraded, "; "))
+	} else {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeDegraded, gramolav1alpha1.AppServiceConditionStatusFalse,
+			gramolav1alpha1.AppServiceConditionReasonSucceeded, "No failures")
+	}
+
+	// Promoted, every component runs the version of the operator
+	if len(outdated) == 0 && len(rolling) == 0 && len(unavailable) == 0 {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypePromoted, gramolav1alpha1.AppServiceConditionStatusTrue,
+			gramolav1alpha1.AppServiceConditionReasonSucceeded, fmt.Sprintf("Version %s is rolled out", version.Version))
+	} else {
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypePromoted, gramolav1alpha1.AppServiceConditionStatusFalse,
+			gramolav1alpha1.AppServiceConditionReasonProgressing, fmt.Sprintf("Version %s is not rolled out yet", version.Version))
+	}
+}
+
+// getRunningBackups returns the names of the AppServiceDataExports of the AppService not finished yet
+func (r *ReconcileAppService) getRunningBackups(instance *gramolav1alpha1.AppService) ([]string, error) {
+	exports := &gramolav1alpha1.AppServiceDataExportList{}
+	if err := r.client.List(context.TODO(), exports, client.InNamespace(instance.Namespace)); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, export := range exports.Items {
+		if export.Spec.AppService == instance.Name && !export.Status.IsFinished() {
+			names = append(names, export.Name)
+		}
+	}
+	return names, nil
+}
+
+// isDeploymentAvailable checks if all the desired replicas of a Deployment are available
+func isDeploymentAvailable(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.AvailableReplicas >= replicas
+}
+
+// isDeploymentRolling checks if a Deployment hasn't finished rolling out its current spec
+func isDeploymentRolling(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration < deployment.Generation ||
+		deployment.Status.UpdatedReplicas < replicas ||
+		deployment.Status.Replicas > deployment.Status.UpdatedReplicas
+}
+
+// isDeploymentStalled checks if a Deployment exceeded its progress deadline
+func isDeploymentStalled(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
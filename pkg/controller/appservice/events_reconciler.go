@@ -3,8 +3,6 @@ package appservice
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"os"
 
 	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
 	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
@@ -13,6 +11,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -21,16 +20,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-// Constants to locate the scripts to update the database
-const (
-	DbScriptsBaseEnvVarName = "DB_SCRIPTS_BASE_DIR"
-	//DbUpdateScriptName      = "events-database-update-0.0.2.sql"
-	//DbScriptsMountPoint = "/operator/scripts"
-)
-
-// DbScriptsBasePath point to the directory where the scripts to update the database should be
-var DbScriptsBasePath = os.Getenv(DbScriptsBaseEnvVarName) + "/db"
-
 // Reconciling Events
 func (r *ReconcileAppService) reconcileEvents(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
 
@@ -49,6 +38,7 @@ func (r *ReconcileAppService) addEvents(instance *gramolav1alpha1.AppService) (r
 				from := &corev1.Secret{}
 				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: databaseSecret.Name, Namespace: databaseSecret.Namespace}, from); err == nil {
 					patch := _deployment.NewEventsDatabaseCredentialsSecretPatch(from)
+					r.deferCredentialRotation(instance, from)
 					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
 						return reconcile.Result{}, err
 					}
@@ -87,7 +77,11 @@ func (r *ReconcileAppService) addEvents(instance *gramolav1alpha1.AppService) (r
 	}
 
 	// PVC for Events Database
-	databasePersistentVolumeClaim := _deployment.NewPersistentVolumeClaim(instance, _deployment.EventsDatabaseServiceName, instance.Namespace, "512Mi")
+	storage := _deployment.GetEventsDatabaseStorage(instance)
+	databasePersistentVolumeClaim := _deployment.NewPersistentVolumeClaim(instance, _deployment.GetObjectName(instance, _deployment.EventsDatabasePersistanceVolumeClaimName), instance.Namespace, storage)
+	if instance.Spec.Database != nil && len(instance.Spec.Database.StorageClass) > 0 {
+		databasePersistentVolumeClaim.Spec.StorageClassName = &instance.Spec.Database.StorageClass
+	}
 	if err := controllerutil.SetControllerReference(instance, databasePersistentVolumeClaim, r.scheme); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -96,15 +90,24 @@ func (r *ReconcileAppService) addEvents(instance *gramolav1alpha1.AppService) (r
 	} else if err == nil {
 		log.Info(fmt.Sprintf("Created %s Persistent Volume Claim", databasePersistentVolumeClaim.Name))
 		r.recorder.Eventf(instance, "Normal", "PVC Created", "Created %s Persistent Volume Claim", databasePersistentVolumeClaim.Name)
+	} else if err := r.resizeEventsDatabaseStorage(instance, storage); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// Adds environment variables from the secret values passed and also mounts a volume with the configmap also passed in
 	if databaseDeployment, err := _deployment.NewEventsDatabaseDeployment(instance, r.scheme); err == nil {
+		r.scaleDeployment(instance, databaseDeployment, nil)
 		if err := r.client.Create(context.TODO(), databaseDeployment); err != nil {
 			if errors.IsAlreadyExists(err) {
 				from := &appsv1.Deployment{}
 				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: databaseDeployment.Name, Namespace: databaseDeployment.Namespace}, from); err == nil {
-					patch := _deployment.NewEventsDatabaseDeploymentPatch(from)
+					previous := from.DeepCopy()
+					replicas := from.Spec.Replicas
+					patch := _deployment.NewEventsDatabaseDeploymentPatch(instance, from)
+					if err := r.deferRollout(instance, previous, from, patch); err != nil {
+						return reconcile.Result{}, err
+					}
+					r.scaleDeployment(instance, from, replicas)
 					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
 						return reconcile.Result{}, err
 					}
@@ -142,11 +145,18 @@ func (r *ReconcileAppService) addEvents(instance *gramolav1alpha1.AppService) (r
 	}
 
 	if eventsDeployment, err := _deployment.NewEventsDeployment(instance, r.scheme); err == nil {
+		r.scaleDeployment(instance, eventsDeployment, nil)
 		if err := r.client.Create(context.TODO(), eventsDeployment); err != nil {
 			if errors.IsAlreadyExists(err) {
 				from := &appsv1.Deployment{}
 				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: eventsDeployment.Name, Namespace: eventsDeployment.Namespace}, from); err == nil {
+					previous := from.DeepCopy()
+					replicas := from.Spec.Replicas
 					patch := _deployment.NewEventsDeploymentPatch(from)
+					if err := r.deferRollout(instance, previous, from, patch); err != nil {
+						return reconcile.Result{}, err
+					}
+					r.scaleDeployment(instance, from, replicas)
 					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
 						return reconcile.Result{}, err
 					}
@@ -208,13 +218,30 @@ func (r *ReconcileAppService) addEvents(instance *gramolav1alpha1.AppService) (r
 	return reconcile.Result{}, nil
 }
 
-func readFile(fileName string) (string, error) {
-	filePath := _deployment.DbScriptsBasePath + "/" + fileName
-	log.Info(fmt.Sprintf("Reading file %s", fileName))
-	data, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		fmt.Println("File reading error", err)
-		return "", err
+// resizeEventsDatabaseStorage grows the events database volume, shrinking is not supported
+func (r *ReconcileAppService) resizeEventsDatabaseStorage(instance *gramolav1alpha1.AppService, storage string) error {
+	from := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, _deployment.EventsDatabasePersistanceVolumeClaimName), Namespace: instance.Namespace}, from); err != nil {
+		return err
+	}
+
+	current := from.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch current.Cmp(resource.MustParse(storage)) {
+	case 0:
+		return nil
+	case 1:
+		r.recorder.Eventf(instance, "Warning", "PVC Not Resized", "%s Persistent Volume Claim can't shrink from %s to %s", from.Name, current.String(), storage)
+		return nil
+	}
+
+	if !r.allowDisruptiveAction(instance, gramolav1alpha1.MaintenanceActionStorageResize, fmt.Sprintf("Resize %s from %s to %s", from.Name, current.String(), storage)) {
+		return nil
+	}
+	patch := _deployment.NewPersistentVolumeClaimResizePatch(from, storage)
+	if err := r.client.Patch(context.TODO(), from, patch); err != nil {
+		return err
 	}
-	return string(data), nil
+	log.Info(fmt.Sprintf("Resized %s Persistent Volume Claim to %s", from.Name, storage))
+	r.recorder.Eventf(instance, "Normal", "PVC Resized", "Resized %s Persistent Volume Claim to %s", from.Name, storage)
+	return nil
 }
@@ -0,0 +1,179 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// addFinalizer adds the finalizer of the AppService with a patch of its metadata only, the spec is owned by the user
+// (or a GitOps tool) and never written by the controller
+func (r *ReconcileAppService) addFinalizer(instance *gramolav1alpha1.AppService) error {
+	if hasFinalizer(instance, gramolav1alpha1.AppServiceFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopy())
+	controllerutil.AddFinalizer(instance, gramolav1alpha1.AppServiceFinalizer)
+	if err := r.client.Patch(context.TODO(), instance, patch); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Added finalizer %s to %s", gramolav1alpha1.AppServiceFinalizer, instance.Name))
+	return nil
+}
+
+// finalize applies the deletion policy of an AppService being deleted and then lets it go, owned objects are garbage
+// collected. Retain keeps the events database volume and credentials, Snapshot waits for a final export of the events
+func (r *ReconcileAppService) finalize(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	if !hasFinalizer(instance, gramolav1alpha1.AppServiceFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	switch instance.Spec.GetDeletionPolicy() {
+	case gramolav1alpha1.DeletionPolicyRetain:
+		if err := r.retainEventsDatabase(instance); err != nil {
+			return r.ManageError(instance, err)
+		}
+	case gramolav1alpha1.DeletionPolicySnapshot:
+		if taken, err := r.takeFinalSnapshot(instance); err != nil {
+			return r.ManageError(instance, err)
+		} else if !taken {
+			return r.ManageSuccess(instance, 5*time.Second, gramolav1alpha1.RequeueEvent,
+				fmt.Sprintf("Taking the final snapshot %s%s", instance.Name, _deployment.FinalSnapshotSuffix))
+		}
+	}
+
+	patch := client.MergeFrom(instance.DeepCopy())
+	controllerutil.RemoveFinalizer(instance, gramolav1alpha1.AppServiceFinalizer)
+	// The validating webhook lets AppServices being deleted through, a denial comes from another admission plugin
+	if err := r.client.Patch(context.TODO(), instance, patch); errors.IsForbidden(err) {
+		return r.ManageError(instance, fmt.Errorf("Removing finalizer %s was denied, remove it by hand if the deletion policy was applied: %v",
+			gramolav1alpha1.AppServiceFinalizer, err))
+	} else if err != nil {
+		return r.ManageError(instance, err)
+	}
+	log.Info(fmt.Sprintf("Removed finalizer %s from %s", gramolav1alpha1.AppServiceFinalizer, instance.Name))
+	return reconcile.Result{}, nil
+}
+
+// retainEventsDatabase removes the owner reference to the AppService from the events database volume and credentials,
+// so they aren't garbage collected, and records the AppService they come from. An AppService created again with the
+// same name finds them
+func (r *ReconcileAppService) retainEventsDatabase(instance *gramolav1alpha1.AppService) error {
+	retained := getEventsDatabaseObjects(
+		_deployment.GetObjectName(instance, _deployment.EventsDatabasePersistanceVolumeClaimName),
+		_deployment.GetObjectName(instance, _deployment.EventsDatabaseCredentialsSecretName))
+	for _, retain := range retained {
+		obj := retain.obj
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: retain.name, Namespace: instance.Namespace}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		meta, ok := obj.(metav1.Object)
+		if !ok {
+			continue
+		}
+		owners := []metav1.OwnerReference{}
+		for _, owner := range meta.GetOwnerReferences() {
+			if owner.UID != instance.UID {
+				owners = append(owners, owner)
+			}
+		}
+		if len(owners) == len(meta.GetOwnerReferences()) {
+			continue
+		}
+		patch := client.MergeFrom(obj.DeepCopyObject())
+		meta.SetOwnerReferences(owners)
+		annotations := meta.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[gramolav1alpha1.RetainedFromAnnotation] = instance.Name
+		meta.SetAnnotations(annotations)
+		if err := r.client.Patch(context.TODO(), obj, patch); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Retained %s %s", retain.name, retain.kind))
+		r.recorder.Eventf(instance, "Normal", retain.kind+" Retained", "Retained %s %s, it's kept after the AppService is deleted", retain.name, retain.kind)
+	}
+	return nil
+}
+
+// takeFinalSnapshot exports the events before the AppService is deleted, the events database is scaled up if it was
+// scaled down. Returns true once the export succeeded
+func (r *ReconcileAppService) takeFinalSnapshot(instance *gramolav1alpha1.AppService) (bool, error) {
+	export := _deployment.NewFinalSnapshotDataExport(instance)
+	if err := r.client.Create(context.TODO(), export); err != nil && !errors.IsAlreadyExists(err) {
+		return false, err
+	} else if err == nil {
+		log.Info(fmt.Sprintf("Created %s AppServiceDataExport", export.Name))
+		r.recorder.Eventf(instance, "Normal", "Final Snapshot Started", "Created %s AppServiceDataExport", export.Name)
+	}
+
+	current := &gramolav1alpha1.AppServiceDataExport{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: export.Name, Namespace: export.Namespace}, current); err != nil {
+		return false, err
+	}
+	if current.Spec.AppService != instance.Name || current.CreationTimestamp.Before(&instance.CreationTimestamp) {
+		return false, fmt.Errorf("AppServiceDataExport %s was not created for this AppService, delete it or change the deletion policy", current.Name)
+	}
+
+	switch current.Status.Phase {
+	case gramolav1alpha1.DataTransferPhaseSucceeded:
+		log.Info(fmt.Sprintf("Final snapshot %s taken: %s", current.Name, current.Status.Message))
+		r.recorder.Eventf(instance, "Normal", "Final Snapshot Taken", "Final snapshot %s taken: %s", current.Name, current.Status.Message)
+		return true, nil
+	case gramolav1alpha1.DataTransferPhaseFailed:
+		return false, fmt.Errorf("Final snapshot %s failed, delete it to retry or change the deletion policy: %s", current.Name, current.Status.Message)
+	}
+
+	// The export needs a running events database and, with DuringOperations, a read-only one
+	if err := r.scaleUpEventsDatabase(instance); err != nil {
+		return false, err
+	}
+	return false, r.reconcileReadOnly(instance)
+}
+
+// scaleUpEventsDatabase gives the events database a replica if it was scaled down to zero
+func (r *ReconcileAppService) scaleUpEventsDatabase(instance *gramolav1alpha1.AppService) error {
+	from := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, _deployment.EventsDatabaseServiceName), Namespace: instance.Namespace}, from); err != nil {
+		return err
+	}
+	if from.Spec.Replicas == nil || *from.Spec.Replicas > 0 {
+		return nil
+	}
+	patch := client.MergeFrom(from.DeepCopy())
+	replicas := int32(1)
+	from.Spec.Replicas = &replicas
+	if err := r.client.Patch(context.TODO(), from, patch); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Scaled %s up for the final snapshot", from.Name))
+	r.recorder.Eventf(instance, "Normal", "Deployment Restored", "Scaled %s up for the final snapshot", from.Name)
+	return nil
+}
+
+// hasFinalizer checks if an AppService has a finalizer
+func hasFinalizer(instance *gramolav1alpha1.AppService, finalizer string) bool {
+	for _, f := range instance.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
@@ -34,12 +34,47 @@ func (r *ReconcileAppService) reconcileFrontend(instance *gramolav1alpha1.AppSer
 }
 
 func (r *ReconcileAppService) addFrontend(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	// The branding goes first so that new frontend pods find it
+	if brandingConfigMap, err := _deployment.NewFrontendBrandingConfigMap(instance, r.scheme); err == nil {
+		if err := r.client.Create(context.TODO(), brandingConfigMap); err != nil {
+			if errors.IsAlreadyExists(err) {
+				from := &corev1.ConfigMap{}
+				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: brandingConfigMap.Name, Namespace: brandingConfigMap.Namespace}, from); err == nil {
+					patch, err := _deployment.NewFrontendBrandingConfigMapPatch(instance, from)
+					if err != nil {
+						return reconcile.Result{}, err
+					}
+					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
+						return reconcile.Result{}, err
+					}
+				}
+			} else {
+				return reconcile.Result{}, err
+			}
+		}
+		// Branding ConfigMap created/updated successfully
+		log.Info(fmt.Sprintf("Created/Updated %s ConfigMap", brandingConfigMap.Name))
+		r.recorder.Eventf(instance, "Normal", "ConfigMap Created/Updated", "Created/Updated %s ConfigMap", brandingConfigMap.Name)
+	} else {
+		return reconcile.Result{}, err
+	}
+
 	if frontendDeployment, err := _deployment.NewFrontendDeployment(instance, r.scheme); err == nil {
+		r.scaleDeployment(instance, frontendDeployment, nil)
 		if err := r.client.Create(context.TODO(), frontendDeployment); err != nil {
 			if errors.IsAlreadyExists(err) {
 				from := &appsv1.Deployment{}
 				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: frontendDeployment.Name, Namespace: frontendDeployment.Namespace}, from); err == nil {
-					patch := _deployment.NewFrontendDeploymentPatch(from)
+					previous := from.DeepCopy()
+					replicas := from.Spec.Replicas
+					patch, err := _deployment.NewFrontendDeploymentPatch(instance, from)
+					if err != nil {
+						return reconcile.Result{}, err
+					}
+					if err := r.deferRollout(instance, previous, from, patch); err != nil {
+						return reconcile.Result{}, err
+					}
+					r.scaleDeployment(instance, from, replicas)
 					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
 						return reconcile.Result{}, err
 					}
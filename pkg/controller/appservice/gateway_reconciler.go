@@ -31,11 +31,18 @@ func (r *ReconcileAppService) reconcileGateway(instance *gramolav1alpha1.AppServ
 
 func (r *ReconcileAppService) addGateway(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
 	if gatewayDeployment, err := _deployment.NewGatewayDeployment(instance, r.scheme); err == nil {
+		r.scaleDeployment(instance, gatewayDeployment, nil)
 		if err := r.client.Create(context.TODO(), gatewayDeployment); err != nil {
 			if errors.IsAlreadyExists(err) {
 				from := &appsv1.Deployment{}
 				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: gatewayDeployment.Name, Namespace: gatewayDeployment.Namespace}, from); err == nil {
+					previous := from.DeepCopy()
+					replicas := from.Spec.Replicas
 					patch := _deployment.NewGatewayDeploymentPatch(from)
+					if err := r.deferRollout(instance, previous, from, patch); err != nil {
+						return reconcile.Result{}, err
+					}
+					r.scaleDeployment(instance, from, replicas)
 					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
 						return reconcile.Result{}, err
 					}
@@ -0,0 +1,151 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	_maintenance "github.com/redhat/gramola-operator/pkg/maintenance"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconciling Maintenance, pending actions are found again in every reconciliation
+func (r *ReconcileAppService) reconcileMaintenance(instance *gramolav1alpha1.AppService) error {
+	instance.Status.PendingActions = nil
+	instance.Status.NextMaintenanceWindow = nil
+	if len(instance.Spec.MaintenanceWindows) == 0 {
+		return nil
+	}
+
+	_, next, err := _maintenance.Check(instance.Spec.MaintenanceWindows, time.Now())
+	if err != nil {
+		return err
+	}
+	instance.Status.NextMaintenanceWindow = &metav1.Time{Time: next}
+	return nil
+}
+
+// allowDisruptiveAction returns true if a disruptive action can run now, otherwise it is listed in status
+// as pending until the next maintenance window
+func (r *ReconcileAppService) allowDisruptiveAction(instance *gramolav1alpha1.AppService, actionType gramolav1alpha1.MaintenanceActionType, description string) bool {
+	if len(instance.Spec.MaintenanceWindows) == 0 {
+		return true
+	}
+
+	open, next, err := _maintenance.Check(instance.Spec.MaintenanceWindows, time.Now())
+	if err != nil || open {
+		// Windows are validated before, an error here can't defer actions forever
+		return true
+	}
+
+	log.Info(fmt.Sprintf("Deferring %s to %s: %s", actionType, next, description))
+	instance.Status.PendingActions = append(instance.Status.PendingActions, gramolav1alpha1.PendingAction{
+		Type:        actionType,
+		Description: description,
+		ScheduledAt: metav1.Time{Time: next},
+	})
+	return false
+}
+
+// maintenanceRequeueAfter returns how long until the window pending actions are scheduled for, 0 if none
+func maintenanceRequeueAfter(instance *gramolav1alpha1.AppService) time.Duration {
+	if len(instance.Status.PendingActions) == 0 {
+		return 0
+	}
+	after := time.Until(instance.Status.PendingActions[0].ScheduledAt.Time)
+	if after < time.Second {
+		after = time.Second
+	}
+	return after
+}
+
+// deferRollout defers the whole patch of a Deployment if it would replace the pods and that has to wait for a maintenance
+// window, current is restored to previous so only what's set afterwards, like the replicas, is patched. A dry run of the
+// patch tells if the pod template changes once defaulted by the API server
+func (r *ReconcileAppService) deferRollout(instance *gramolav1alpha1.AppService, previous *appsv1.Deployment, current *appsv1.Deployment, patch client.Patch) error {
+	if len(instance.Spec.MaintenanceWindows) == 0 {
+		return nil
+	}
+
+	patched := current.DeepCopy()
+	if err := r.client.Patch(context.TODO(), patched, patch, client.DryRunAll); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(previous.Spec.Template, patched.Spec.Template) {
+		return nil
+	}
+
+	actionType, description := gramolav1alpha1.MaintenanceActionRollout, fmt.Sprintf("Roll out %s", current.Name)
+	if images := changedImages(&previous.Spec.Template.Spec, &patched.Spec.Template.Spec); len(images) > 0 {
+		actionType, description = gramolav1alpha1.MaintenanceActionImageUpdate, fmt.Sprintf("Update images of %s: %s", current.Name, strings.Join(images, ", "))
+	}
+	if !r.allowDisruptiveAction(instance, actionType, description) {
+		previous.DeepCopyInto(current)
+	}
+	return nil
+}
+
+// changedImages returns the containers, init containers included, whose image changes from previous to current
+// as `name from image to image`
+func changedImages(previous *corev1.PodSpec, current *corev1.PodSpec) []string {
+	images := map[string]string{}
+	for _, container := range append(previous.InitContainers, previous.Containers...) {
+		images[container.Name] = container.Image
+	}
+	changed := []string{}
+	for _, container := range append(current.InitContainers, current.Containers...) {
+		if image, ok := images[container.Name]; !ok {
+			changed = append(changed, fmt.Sprintf("%s to %s", container.Name, container.Image))
+		} else if image != container.Image {
+			changed = append(changed, fmt.Sprintf("%s from %s to %s", container.Name, image, container.Image))
+		}
+	}
+	return changed
+}
+
+// deferCredentialRotation drops the credentials of a Secret being patched if they change and have to wait for a maintenance window
+func (r *ReconcileAppService) deferCredentialRotation(instance *gramolav1alpha1.AppService, current *corev1.Secret) {
+	rotated := false
+	for k, v := range current.StringData {
+		rotated = rotated || string(current.Data[k]) != v
+	}
+	if rotated &&
+		!r.allowDisruptiveAction(instance, gramolav1alpha1.MaintenanceActionCredentialRotation, fmt.Sprintf("Rotate credentials in %s", current.Name)) {
+		current.StringData = nil
+	}
+}
+
+// showMaintenancePage points the frontend Route to the maintenance page during a migration
+func (r *ReconcileAppService) showMaintenancePage(instance *gramolav1alpha1.AppService, reason gramolav1alpha1.MaintenancePageReason, operation string) error {
+	shown, err := _maintenance.EnablePage(r.client, r.scheme, instance, reason, operation)
+	if err != nil {
+		return err
+	}
+	if shown {
+		log.Info(fmt.Sprintf("Showing maintenance page during %s", operation))
+		r.recorder.Eventf(instance, "Normal", "Maintenance Page Shown", "Frontend Route points to %s during %s", _deployment.GetObjectName(instance, _deployment.MaintenancePageName), operation)
+	}
+	return nil
+}
+
+// hideMaintenancePage points the frontend Route back to the frontend once a migration is done
+func (r *ReconcileAppService) hideMaintenancePage(instance *gramolav1alpha1.AppService, reason gramolav1alpha1.MaintenancePageReason, operation string) error {
+	hidden, err := _maintenance.DisablePage(r.client, instance, reason, operation)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		log.Info(fmt.Sprintf("Hiding maintenance page after %s", operation))
+		r.recorder.Eventf(instance, "Normal", "Maintenance Page Hidden", "Frontend Route points to %s after %s", _deployment.GetObjectName(instance, _deployment.FrontendServiceName), operation)
+	}
+	return nil
+}
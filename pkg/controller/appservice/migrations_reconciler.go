@@ -0,0 +1,395 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_database "github.com/redhat/gramola-operator/pkg/database"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	_migrations "github.com/redhat/gramola-operator/pkg/migrations"
+	util "github.com/redhat/gramola-operator/pkg/util"
+	version "github.com/redhat/gramola-operator/version"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Operation recorded in the maintenance page status while migrations run
+const maintenancePageMigrations = "migrations"
+
+// migrationsResult tells if the migrations could be reconciled
+type migrationsResult int
+
+// Results of reconcileMigrations
+const (
+	// migrationsFailed migrations couldn't be reconciled, the error says why
+	migrationsFailed migrationsResult = iota
+	// migrationsWaitingForDatabase the events database is not ready yet
+	migrationsWaitingForDatabase
+	// migrationsWaitingForRollout the events database pod doesn't copy the scripts of image migration sources yet
+	migrationsWaitingForRollout
+	// migrationsWaitingForApproval migrations are pending and approval is Manual
+	migrationsWaitingForApproval
+	// migrationsReconciled migrations ran, or were deferred to a maintenance window
+	migrationsReconciled
+)
+
+// Reconciling Migrations, runs in order the migrations not run successfully before (once approved if approval is Manual)
+func (r *ReconcileAppService) reconcileMigrations(instance *gramolav1alpha1.AppService) (migrationsResult, error) {
+	pod, err := _database.GetReadyEventsDatabasePod(r.client, instance)
+	if err != nil {
+		return migrationsFailed, err
+	}
+	if pod == nil {
+		return migrationsWaitingForDatabase, nil
+	}
+	// The rollout of the init containers may wait for a maintenance window
+	if sources := missingMigrationSources(instance, pod); len(sources) > 0 {
+		log.Info(fmt.Sprintf("Waiting for %s to copy the scripts of migration sources %s", pod.Name, strings.Join(sources, ", ")))
+		return migrationsWaitingForRollout, nil
+	}
+
+	migrations, err := r.getMigrations(instance, pod)
+	if err != nil {
+		return migrationsFailed, err
+	}
+
+	manifestData, err := _deployment.GetEventsDatabaseSchemaManifest()
+	if err != nil {
+		return migrationsFailed, err
+	}
+	manifest, err := _database.ParseSchemaManifest(manifestData)
+	if err != nil {
+		return migrationsFailed, err
+	}
+
+	scriptContext, err := r.getScriptContext(instance)
+	if err != nil {
+		return migrationsFailed, err
+	}
+
+	// Render every script first, template errors are recorded per pending script and nothing is run. Scripts run before
+	// that no longer render keep their last rendering in the scripts ConfigMap
+	rendered := []_database.Migration{}
+	failed := []string{}
+	unrendered := []string{}
+	for _, migration := range migrations {
+		if migration.Go != nil {
+			rendered = append(rendered, migration)
+			continue
+		}
+		script, err := _database.RenderScript(&migration, scriptContext)
+		if err != nil {
+			log.Error(err, "Error rendering script", "script", migration.Name)
+			if !migrationWasRun(instance, migration.Name) {
+				recordScriptRun(instance, gramolav1alpha1.DatabaseScriptRun{
+					Script:  migration.Name,
+					Status:  gramolav1alpha1.DatabaseUpdateStatusFailed,
+					Message: err.Error(),
+				})
+				failed = append(failed, migration.Name)
+			}
+			unrendered = append(unrendered, migration.Name)
+			continue
+		}
+		migration.Script = script
+		rendered = append(rendered, migration)
+	}
+	// The schema is verified after the last of Gramola's migrations and after every migration of the sources
+	lastBuiltin := ""
+	for _, migration := range rendered {
+		if migration.Builtin {
+			lastBuiltin = migration.Name
+		}
+	}
+
+	if err := r.updateEventsDatabaseScriptsConfigMap(instance, rendered, unrendered); err != nil {
+		return migrationsFailed, err
+	}
+
+	if len(failed) > 0 {
+		instance.Status.EventsDatabaseUpdated = gramolav1alpha1.DatabaseUpdateStatusFailed
+		return migrationsFailed, fmt.Errorf("Unable to render scripts %s", strings.Join(failed, ", "))
+	}
+
+	if approved := r.approveMigrations(instance, rendered); !approved {
+		return migrationsWaitingForApproval, nil
+	}
+
+	pending := pendingMigrationNames(instance, rendered)
+	if len(pending) > 0 &&
+		!r.allowDisruptiveAction(instance, gramolav1alpha1.MaintenanceActionMigration, fmt.Sprintf("Run migrations %s", strings.Join(pending, ", "))) {
+		return migrationsReconciled, nil
+	}
+
+	// Users see the maintenance page instead of errors while migrations run, it stays if one fails until they all succeed
+	if len(pending) > 0 {
+		if err := r.showMaintenancePage(instance, gramolav1alpha1.MaintenancePageReasonMigration, maintenancePageMigrations); err != nil {
+			return migrationsFailed, err
+		}
+	}
+
+	// Users can read events but not change them while migrations run, read-only is lifted even if one fails
+	if len(pending) > 0 && instance.Spec.GetReadOnlyMode() == gramolav1alpha1.ReadOnlyModeDuringOperations {
+		if err := r.setReadOnly(instance, pod, true, "Migrations are running"); err != nil {
+			return migrationsFailed, err
+		}
+		defer func() {
+			if err := r.reconcileReadOnly(instance); err != nil {
+				log.Error(err, "Unable to lift read-only mode after migrations")
+			}
+		}()
+	}
+
+	for i := range rendered {
+		migration := &rendered[i]
+		if migrationWasRun(instance, migration.Name) {
+			continue
+		}
+
+		// TODO Backup DB
+
+		// Start the Script Run
+		scriptRun := gramolav1alpha1.DatabaseScriptRun{
+			Script: migration.Name,
+			Status: gramolav1alpha1.DatabaseUpdateStatusUnknown,
+		}
+		if migration.Go != nil {
+			err = _database.RunGoMigration(pod, migration, scriptContext)
+		} else {
+			var out string
+			out, err = _database.RunMigration(pod, migration)
+			log.Info(fmt.Sprintf("Ran %s, stdout: %s", migration.Name, out))
+		}
+		// The manifest describes the schema once all of Gramola's migrations have run
+		if err == nil && (!migration.Builtin || migration.Name == lastBuiltin) {
+			err = verifySchema(pod, manifest, migration)
+		}
+		if err != nil {
+			scriptRun.Status = gramolav1alpha1.DatabaseUpdateStatusFailed
+			scriptRun.Message = err.Error()
+			recordScriptRun(instance, scriptRun)
+			instance.Status.EventsDatabaseUpdated = gramolav1alpha1.DatabaseUpdateStatusFailed
+			return migrationsFailed, err
+		}
+
+		scriptRun.Status = gramolav1alpha1.DatabaseUpdateStatusSucceeded
+		recordScriptRun(instance, scriptRun)
+		instance.Status.EventsDatabaseUpdated = gramolav1alpha1.DatabaseUpdateStatusSucceeded
+		r.recorder.Eventf(instance, "Normal", "Migration Run", "Ran %s on %s", migration.Name, pod.Name)
+	}
+
+	if err := r.hideMaintenancePage(instance, gramolav1alpha1.MaintenancePageReasonMigration, maintenancePageMigrations); err != nil {
+		return migrationsFailed, err
+	}
+
+	return migrationsReconciled, nil
+}
+
+// approveMigrations returns true if the pending migrations can run. In Manual mode they are listed with their digest
+// in status and the MigrationPending condition until the approval annotation matches the digest
+func (r *ReconcileAppService) approveMigrations(instance *gramolav1alpha1.AppService, migrations []_database.Migration) bool {
+	pending := []_database.Migration{}
+	for _, migration := range migrations {
+		if !migrationWasRun(instance, migration.Name) {
+			pending = append(pending, migration)
+		}
+	}
+	names := pendingMigrationNames(instance, pending)
+
+	if len(pending) == 0 || !instance.Spec.IsMigrationApprovalManual() {
+		instance.Status.PendingMigrations = nil
+		if instance.Status.GetCondition(gramolav1alpha1.AppServiceConditionTypeMigrationPending) != nil {
+			instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeMigrationPending, gramolav1alpha1.AppServiceConditionStatusFalse,
+				gramolav1alpha1.AppServiceConditionReasonSucceeded, "No migrations pending")
+		}
+		return true
+	}
+
+	digest := _database.Digest(pending)
+	if instance.Annotations[gramolav1alpha1.MigrationApprovalAnnotation] == digest {
+		log.Info(fmt.Sprintf("Migrations %s approved", strings.Join(names, ", ")))
+		r.recorder.Eventf(instance, "Normal", "Migrations Approved", "Running approved migrations %s", strings.Join(names, ", "))
+		instance.Status.PendingMigrations = nil
+		instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeMigrationPending, gramolav1alpha1.AppServiceConditionStatusFalse,
+			gramolav1alpha1.AppServiceConditionReasonProgressing, fmt.Sprintf("Running approved migrations %s", digest))
+		return true
+	}
+
+	if instance.Status.PendingMigrations == nil || instance.Status.PendingMigrations.Digest != digest {
+		r.recorder.Eventf(instance, "Normal", "Migrations Pending", "Migrations %s wait for approval, annotate with %s=%s",
+			strings.Join(names, ", "), gramolav1alpha1.MigrationApprovalAnnotation, digest)
+	}
+	instance.Status.PendingMigrations = &gramolav1alpha1.PendingMigrations{
+		Scripts: names,
+		Digest:  digest,
+	}
+	instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeMigrationPending, gramolav1alpha1.AppServiceConditionStatusTrue,
+		gramolav1alpha1.AppServiceConditionReasonWaiting, fmt.Sprintf("%d migrations wait for approval, annotate with %s=%s",
+			len(pending), gramolav1alpha1.MigrationApprovalAnnotation, digest))
+	return false
+}
+
+// getMigrations returns Gramola's scripts and Go migrations in version order followed by the scripts of each migration source in name order
+func (r *ReconcileAppService) getMigrations(instance *gramolav1alpha1.AppService, pod *corev1.Pod) ([]_database.Migration, error) {
+	scripts, err := _deployment.GetDatabaseScriptsMap()
+	if err != nil {
+		return nil, err
+	}
+	migrations := []_database.Migration{
+		{Name: _deployment.EventsDatabaseUpdateScriptName, Version: _deployment.EventsDatabaseUpdateScriptVersion, Script: scripts[_deployment.EventsDatabaseUpdateScriptName], Builtin: true},
+	}
+	for _, goMigration := range _migrations.Registered() {
+		migrations = append(migrations, _database.Migration{Name: goMigration.Name(), Version: goMigration.Version, Go: goMigration, Builtin: true})
+	}
+	// Scripts run before Go migrations of the same version
+	var versionErr error
+	sort.SliceStable(migrations, func(i, j int) bool {
+		cmp, err := util.CompareVersions(migrations[i].Version, migrations[j].Version)
+		if err != nil {
+			versionErr = err
+		}
+		return cmp < 0
+	})
+	if versionErr != nil {
+		return nil, versionErr
+	}
+
+	names := map[string]bool{}
+	for i := range instance.Spec.MigrationSources {
+		source := &instance.Spec.MigrationSources[i]
+		if err := source.Validate(); err != nil {
+			return nil, err
+		}
+		if names[source.Name] {
+			return nil, fmt.Errorf("Duplicated migration source %s", source.Name)
+		}
+		names[source.Name] = true
+
+		var sourceScripts map[string]string
+		if len(source.ConfigMap) > 0 {
+			configMap := &corev1.ConfigMap{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: source.ConfigMap, Namespace: instance.Namespace}, configMap); err != nil {
+				return nil, fmt.Errorf("Unable to get ConfigMap %s of migration source %s: %v", source.ConfigMap, source.Name, err)
+			}
+			sourceScripts = configMap.Data
+		} else {
+			// Scripts are copied by an init container of the database pod
+			if sourceScripts, err = _database.ReadMigrationScripts(pod, path.Join(_deployment.EventsDatabaseMigrationsMountPath, source.Name)); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, script := range _database.SortedScriptNames(sourceScripts) {
+			migrations = append(migrations, _database.Migration{
+				Name:   _database.MigrationName(source.Name, script),
+				Script: sourceScripts[script],
+			})
+		}
+	}
+
+	return migrations, nil
+}
+
+// missingMigrationSources returns the image migration sources whose scripts the events database pod doesn't copy, with
+// their current image, until the Deployment rolls out its init containers
+func missingMigrationSources(instance *gramolav1alpha1.AppService, pod *corev1.Pod) []string {
+	images := map[string]string{}
+	for _, container := range pod.Spec.InitContainers {
+		images[container.Name] = container.Image
+	}
+	missing := []string{}
+	for _, source := range instance.Spec.MigrationSources {
+		if source.Image != nil && images[_deployment.GetMigrationsInitContainerName(source.Name)] != source.Image.Image {
+			missing = append(missing, source.Name)
+		}
+	}
+	return missing
+}
+
+// getScriptContext returns the context scripts are rendered with, credentials come from the Secret in the cluster
+func (r *ReconcileAppService) getScriptContext(instance *gramolav1alpha1.AppService) (*_database.ScriptContext, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, _deployment.EventsDatabaseCredentialsSecretName), Namespace: instance.Namespace}, secret); err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for k, v := range secret.Data {
+		values[k] = string(v)
+	}
+	return _database.NewScriptContext(instance, values, version.Version), nil
+}
+
+// updateEventsDatabaseScriptsConfigMap projects the migrations into the events database scripts ConfigMap, the scripts
+// in unrendered keep their current content if any
+func (r *ReconcileAppService) updateEventsDatabaseScriptsConfigMap(instance *gramolav1alpha1.AppService, migrations []_database.Migration, unrendered []string) error {
+	from := &corev1.ConfigMap{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, _deployment.EventsDatabaseScriptsConfigMapName), Namespace: instance.Namespace}, from); err != nil {
+		return err
+	}
+
+	scripts := map[string]string{}
+	for _, migration := range migrations {
+		if migration.Go == nil {
+			scripts[migration.Name] = migration.Script
+		}
+	}
+	for _, name := range unrendered {
+		if script, ok := from.Data[name]; ok {
+			scripts[name] = script
+		}
+	}
+	patch := _deployment.NewEventsDatabaseScriptsConfigMapDataPatch(from, scripts)
+	return r.client.Patch(context.TODO(), from, patch)
+}
+
+// verifySchema introspects the schema after a migration and fails with the differences with the manifest
+func verifySchema(pod *corev1.Pod, manifest *_database.SchemaManifest, migration *_database.Migration) error {
+	schema, err := _database.IntrospectSchema(pod)
+	if err != nil {
+		return err
+	}
+	if diff := schema.Diff(manifest); len(diff) > 0 {
+		return fmt.Errorf("Schema doesn't match the manifest after %s: %s", migration.Name, strings.Join(diff, "; "))
+	}
+	return nil
+}
+
+// pendingMigrationNames returns the names of the migrations not run successfully before
+func pendingMigrationNames(instance *gramolav1alpha1.AppService, migrations []_database.Migration) []string {
+	names := []string{}
+	for _, migration := range migrations {
+		if !migrationWasRun(instance, migration.Name) {
+			names = append(names, migration.Name)
+		}
+	}
+	return names
+}
+
+// migrationWasRun checks if a migration was run successfully before
+func migrationWasRun(instance *gramolav1alpha1.AppService, name string) bool {
+	for i := range instance.Status.EventsDatabaseScriptRuns {
+		if instance.Status.EventsDatabaseScriptRuns[i].Script == name &&
+			instance.Status.EventsDatabaseScriptRuns[i].Status == gramolav1alpha1.DatabaseUpdateStatusSucceeded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordScriptRun appends a run to the history, a new run of a failed script replaces its failed run
+func recordScriptRun(instance *gramolav1alpha1.AppService, scriptRun gramolav1alpha1.DatabaseScriptRun) {
+	for i := range instance.Status.EventsDatabaseScriptRuns {
+		if instance.Status.EventsDatabaseScriptRuns[i].Script == scriptRun.Script &&
+			instance.Status.EventsDatabaseScriptRuns[i].Status == gramolav1alpha1.DatabaseUpdateStatusFailed {
+			instance.Status.EventsDatabaseScriptRuns[i] = scriptRun
+			return
+		}
+	}
+	instance.Status.EventsDatabaseScriptRuns = append(instance.Status.EventsDatabaseScriptRuns, scriptRun)
+}
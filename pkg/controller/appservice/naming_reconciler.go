@@ -0,0 +1,122 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// eventsDatabaseObject is an object holding the events database of an AppService
+type eventsDatabaseObject struct {
+	kind string
+	name string
+	obj  runtime.Object
+}
+
+// getEventsDatabaseObjects returns the volume and the credentials of an events database given their names, the objects
+// kept with the Retain deletion policy
+func getEventsDatabaseObjects(claimName string, secretName string) []eventsDatabaseObject {
+	return []eventsDatabaseObject{
+		{"PersistentVolumeClaim", claimName, &corev1.PersistentVolumeClaim{}},
+		{"Secret", secretName, &corev1.Secret{}},
+	}
+}
+
+// reconcileNaming records once, with a patch of the metadata, if the objects of the AppService keep the unprefixed names.
+// They do if the AppService owns the unprefixed events database, it was reconciled by an older operator, or if that
+// database was retained from an AppService with the same name. Retained events databases are then adopted
+func (r *ReconcileAppService) reconcileNaming(instance *gramolav1alpha1.AppService) error {
+	if _, found := instance.Annotations[gramolav1alpha1.LegacyNamesAnnotation]; !found {
+		legacy, err := r.hasLegacyEventsDatabase(instance)
+		if err != nil {
+			return err
+		}
+		patch := client.MergeFrom(instance.DeepCopy())
+		if instance.Annotations == nil {
+			instance.Annotations = map[string]string{}
+		}
+		instance.Annotations[gramolav1alpha1.LegacyNamesAnnotation] = strconv.FormatBool(legacy)
+		if err := r.client.Patch(context.TODO(), instance, patch); err != nil {
+			return err
+		}
+		if legacy {
+			log.Info(fmt.Sprintf("%s keeps the legacy names of its objects", instance.Name))
+			r.recorder.Eventf(instance, "Normal", "Legacy Names Kept", "Objects keep their unprefixed names, such as %s", _deployment.EventsDatabaseServiceName)
+		}
+	}
+
+	return r.adoptEventsDatabase(instance)
+}
+
+// hasLegacyEventsDatabase checks if the unprefixed events database belongs to the AppService, it's owned by it or was
+// retained from an AppService with the same name
+func (r *ReconcileAppService) hasLegacyEventsDatabase(instance *gramolav1alpha1.AppService) (bool, error) {
+	legacy := getEventsDatabaseObjects(_deployment.EventsDatabasePersistanceVolumeClaimName, _deployment.EventsDatabaseCredentialsSecretName)
+	for _, object := range legacy {
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: object.name, Namespace: instance.Namespace}, object.obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		meta, ok := object.obj.(metav1.Object)
+		if !ok {
+			continue
+		}
+		if owner := metav1.GetControllerOf(meta); owner != nil {
+			if owner.UID == instance.UID {
+				return true, nil
+			}
+		} else if meta.GetAnnotations()[gramolav1alpha1.RetainedFromAnnotation] == instance.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// adoptEventsDatabase sets the AppService as the controller of the events database volume and credentials retained
+// from an AppService with the same name, they're garbage collected with it again
+func (r *ReconcileAppService) adoptEventsDatabase(instance *gramolav1alpha1.AppService) error {
+	retained := getEventsDatabaseObjects(
+		_deployment.GetObjectName(instance, _deployment.EventsDatabasePersistanceVolumeClaimName),
+		_deployment.GetObjectName(instance, _deployment.EventsDatabaseCredentialsSecretName))
+	for _, adopt := range retained {
+		obj := adopt.obj
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: adopt.name, Namespace: instance.Namespace}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		meta, ok := obj.(metav1.Object)
+		if !ok || metav1.GetControllerOf(meta) != nil || meta.GetAnnotations()[gramolav1alpha1.RetainedFromAnnotation] != instance.Name {
+			continue
+		}
+		patch := client.MergeFrom(obj.DeepCopyObject())
+		if err := controllerutil.SetControllerReference(instance, meta, r.scheme); err != nil {
+			return err
+		}
+		annotations := meta.GetAnnotations()
+		delete(annotations, gramolav1alpha1.RetainedFromAnnotation)
+		meta.SetAnnotations(annotations)
+		if err := r.client.Patch(context.TODO(), obj, patch); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Adopted %s %s", adopt.name, adopt.kind))
+		r.recorder.Eventf(instance, "Normal", adopt.kind+" Adopted", "Adopted %s %s retained from a previous AppService %s", adopt.name, adopt.kind, instance.Name)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package appservice
+
+import (
+	"fmt"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_database "github.com/redhat/gramola-operator/pkg/database"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Reconciling Read Only, the events database is read-only if the mode is Always or, if it is DuringOperations, while backups run.
+// It runs in every reconciliation so that read-only is lifted even if the operator restarted in the middle of an operation
+func (r *ReconcileAppService) reconcileReadOnly(instance *gramolav1alpha1.AppService) error {
+	pod, err := _database.GetReadyEventsDatabasePod(r.client, instance)
+	if err != nil || pod == nil {
+		return err
+	}
+
+	readOnly, message, err := r.getReadOnly(instance)
+	if err != nil {
+		return err
+	}
+	return r.setReadOnly(instance, pod, readOnly, message)
+}
+
+// getReadOnly returns if the events database should be read-only, and why, regardless of migrations
+func (r *ReconcileAppService) getReadOnly(instance *gramolav1alpha1.AppService) (bool, string, error) {
+	switch instance.Spec.GetReadOnlyMode() {
+	case gramolav1alpha1.ReadOnlyModeAlways:
+		return true, "Read-only mode is Always", nil
+	case gramolav1alpha1.ReadOnlyModeDuringOperations:
+		backups, err := r.getRunningBackups(instance)
+		if err != nil {
+			return false, "", err
+		}
+		if len(backups) > 0 {
+			return true, fmt.Sprintf("Backup %s is running", backups[0]), nil
+		}
+		return false, "No backup or migration is running", nil
+	}
+	return false, "Read-only mode is Never", nil
+}
+
+// setReadOnly makes the events database read-only, or read-write, if it isn't already and updates the ReadOnly condition
+func (r *ReconcileAppService) setReadOnly(instance *gramolav1alpha1.AppService, pod *corev1.Pod, readOnly bool, message string) error {
+	current, err := _database.IsReadOnly(pod)
+	if err != nil {
+		return err
+	}
+	if current != readOnly {
+		if err := _database.SetReadOnly(pod, readOnly); err != nil {
+			return err
+		}
+		if readOnly {
+			log.Info(fmt.Sprintf("Events database is read-only: %s", message))
+			r.recorder.Eventf(instance, "Normal", "Read Only Enabled", "Events database is read-only: %s", message)
+		} else {
+			log.Info(fmt.Sprintf("Events database is read-write: %s", message))
+			r.recorder.Eventf(instance, "Normal", "Read Only Disabled", "Events database is read-write: %s", message)
+		}
+	}
+
+	status := gramolav1alpha1.AppServiceConditionStatusFalse
+	if readOnly {
+		status = gramolav1alpha1.AppServiceConditionStatusTrue
+	}
+	instance.Status.SetCondition(gramolav1alpha1.AppServiceConditionTypeReadOnly, status, gramolav1alpha1.AppServiceConditionReasonSucceeded, message)
+	return nil
+}
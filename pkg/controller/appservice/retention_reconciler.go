@@ -0,0 +1,151 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_database "github.com/redhat/gramola-operator/pkg/database"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciling Retention
+func (r *ReconcileAppService) reconcileRetention(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	if instance.Spec.Retention == nil {
+		if result, err := r.removeRetention(instance); err != nil {
+			return result, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if result, err := r.addRetention(instance); err != nil {
+		return result, err
+	}
+
+	if err := r.updateRetentionStatus(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Success
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileAppService) addRetention(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	command := _database.PurgeEventsCommand(instance.Spec.Retention.Days)
+	if retentionCronJob, err := _deployment.NewEventsDatabaseRetentionCronJob(instance, command, r.scheme); err == nil {
+		// Purges can't run while the events database is scaled down to zero
+		suspend := isScaledDown(instance, _deployment.EventsDatabaseServiceName)
+		retentionCronJob.Spec.Suspend = &suspend
+		if err := r.client.Create(context.TODO(), retentionCronJob); err != nil {
+			if errors.IsAlreadyExists(err) {
+				from := &batchv1beta1.CronJob{}
+				if err = r.client.Get(context.TODO(), types.NamespacedName{Name: retentionCronJob.Name, Namespace: retentionCronJob.Namespace}, from); err == nil {
+					patch := _deployment.NewEventsDatabaseRetentionCronJobPatch(from, retentionCronJob)
+					if err := r.client.Patch(context.TODO(), from, patch); err != nil {
+						return reconcile.Result{}, err
+					}
+				}
+			} else {
+				return reconcile.Result{}, err
+			}
+		}
+		// Retention CronJob created/updated successfully
+		log.Info(fmt.Sprintf("Created/Updated %s CronJob", retentionCronJob.Name))
+		r.recorder.Eventf(instance, "Normal", "CronJob Created/Updated", "Created/Updated %s CronJob", retentionCronJob.Name)
+	} else {
+		return reconcile.Result{}, err
+	}
+
+	//Success
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileAppService) removeRetention(instance *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	retentionCronJob := &batchv1beta1.CronJob{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, _deployment.EventsDatabaseRetentionCronJobName), Namespace: instance.Namespace}, retentionCronJob); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if err := r.client.Delete(context.TODO(), retentionCronJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.Info(fmt.Sprintf("Deleted %s CronJob", retentionCronJob.Name))
+	r.recorder.Eventf(instance, "Normal", "CronJob Deleted", "Deleted %s CronJob", retentionCronJob.Name)
+
+	return reconcile.Result{}, nil
+}
+
+// updateRetentionStatus records the result of the last purge Job that finished in the status of instance
+func (r *ReconcileAppService) updateRetentionStatus(instance *gramolav1alpha1.AppService) error {
+	jobList := &batchv1.JobList{}
+	listOps := []client.ListOption{
+		client.InNamespace(instance.Namespace),
+		client.MatchingLabels(_deployment.GetAppServiceSelector(instance, _deployment.EventsDatabaseRetentionCronJobName)),
+	}
+	if err := r.client.List(context.TODO(), jobList, listOps...); err != nil {
+		return err
+	}
+
+	var last *batchv1.Job
+	var lastFinishTime metav1.Time
+	for i := range jobList.Items {
+		if finishTime, finished := getJobFinishTime(&jobList.Items[i]); finished && (last == nil || finishTime.After(lastFinishTime.Time)) {
+			last, lastFinishTime = &jobList.Items[i], finishTime
+		}
+	}
+	if last == nil || (instance.Status.Retention != nil && instance.Status.Retention.LastJob == last.Name) {
+		return nil
+	}
+
+	result, err := _database.GetJobResult(r.client, last)
+	if err != nil {
+		return err
+	}
+
+	status := &gramolav1alpha1.RetentionStatus{
+		LastJob:       last.Name,
+		LastRunTime:   lastFinishTime,
+		LastRunStatus: gramolav1alpha1.DatabaseUpdateStatusSucceeded,
+	}
+	if result.Succeeded {
+		if status.LastPurgedRows, err = _database.ParseRowCount(result.Message); err != nil {
+			status.LastRunStatus = gramolav1alpha1.DatabaseUpdateStatusUnknown
+			status.Message = err.Error()
+		} else {
+			status.Message = fmt.Sprintf("Purged %d past events", status.LastPurgedRows)
+		}
+	} else {
+		status.LastRunStatus = gramolav1alpha1.DatabaseUpdateStatusFailed
+		status.Message = result.Message
+		r.recorder.Eventf(instance, "Warning", "RetentionFailed", "Job %s failed: %s", last.Name, result.Message)
+	}
+	instance.Status.Retention = status
+
+	return nil
+}
+
+// getJobFinishTime returns the time a Job completed or failed, false if it is still running
+func getJobFinishTime(job *batchv1.Job) (metav1.Time, bool) {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			if job.Status.CompletionTime != nil {
+				return *job.Status.CompletionTime, true
+			}
+			return condition.LastTransitionTime, true
+		}
+	}
+	return metav1.Time{}, false
+}
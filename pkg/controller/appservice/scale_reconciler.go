@@ -0,0 +1,70 @@
+package appservice
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// scaledDownReason returns why the AppService is scaled down to zero, empty if it runs
+func scaledDownReason(instance *gramolav1alpha1.AppService) string {
+	if !instance.Spec.Enabled {
+		return "AppService is disabled"
+	}
+	if instance.Status.Sleep != nil && instance.Status.Sleep.Asleep {
+		return fmt.Sprintf("AppService is asleep until %s", instance.Status.Sleep.NextTransition.Format(time.RFC3339))
+	}
+	return ""
+}
+
+// isScaledDown checks if the Deployment of a component of the AppService has to be scaled down to zero
+func isScaledDown(instance *gramolav1alpha1.AppService, component string) bool {
+	if len(scaledDownReason(instance)) == 0 {
+		return false
+	}
+	return component != _deployment.EventsDatabaseServiceName || instance.Spec.Database == nil || !instance.Spec.Database.KeepRunning
+}
+
+// scaleDeployment scales a Deployment being created or patched down to zero, recording the replicas it had before the patch
+// (or the desired ones if none) to restore them once the AppService runs again. Previous is nil for Deployments being created
+func (r *ReconcileAppService) scaleDeployment(instance *gramolav1alpha1.AppService, current *appsv1.Deployment, previous *int32) {
+	saved, scaledDown := current.Annotations[gramolav1alpha1.ScaledDownReplicasAnnotation]
+
+	if isScaledDown(instance, current.Labels["component"]) {
+		if !scaledDown {
+			replicas := int32(1)
+			if previous != nil && *previous > 0 {
+				replicas = *previous
+			} else if current.Spec.Replicas != nil && *current.Spec.Replicas > 0 {
+				replicas = *current.Spec.Replicas
+			}
+			if current.Annotations == nil {
+				current.Annotations = map[string]string{}
+			}
+			current.Annotations[gramolav1alpha1.ScaledDownReplicasAnnotation] = strconv.Itoa(int(replicas))
+			if previous != nil {
+				log.Info(fmt.Sprintf("Scaling %s down to zero: %s", current.Name, scaledDownReason(instance)))
+				r.recorder.Eventf(instance, "Normal", "Deployment Scaled Down", "Scaled %s down to zero: %s", current.Name, scaledDownReason(instance))
+			}
+		}
+		zero := int32(0)
+		current.Spec.Replicas = &zero
+		return
+	}
+
+	if scaledDown {
+		delete(current.Annotations, gramolav1alpha1.ScaledDownReplicasAnnotation)
+		restored := int32(1)
+		if replicas, err := strconv.Atoi(saved); err == nil && replicas > 0 {
+			restored = int32(replicas)
+		}
+		current.Spec.Replicas = &restored
+		log.Info(fmt.Sprintf("Restoring %s to %d replicas", current.Name, restored))
+		r.recorder.Eventf(instance, "Normal", "Deployment Restored", "Restored %s to %d replicas", current.Name, restored)
+	}
+}
@@ -0,0 +1,77 @@
+package appservice
+
+import (
+	"fmt"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_maintenance "github.com/redhat/gramola-operator/pkg/maintenance"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reconciling Sleep, the sleep state follows the schedule unless the AppService is kept awake by the wake-up annotation.
+// Deployments are scaled down to zero while asleep, see scaledDownReason
+func (r *ReconcileAppService) reconcileSleep(instance *gramolav1alpha1.AppService) error {
+	if instance.Spec.Schedule == nil {
+		instance.Status.Sleep = nil
+		return nil
+	}
+
+	now := time.Now()
+	asleep, next, err := _maintenance.CheckSleep(instance.Spec.Schedule, now)
+	if err != nil {
+		return err
+	}
+	wakeUpUntil, err := getWakeUpUntil(instance)
+	if err != nil {
+		return err
+	}
+
+	status := &gramolav1alpha1.SleepStatus{NextTransition: metav1.NewTime(next)}
+	if wakeUpUntil != nil && wakeUpUntil.After(now) {
+		status.WakeUpUntil = &metav1.Time{Time: *wakeUpUntil}
+	} else {
+		status.Asleep = asleep
+	}
+
+	wasAsleep := instance.Status.Sleep != nil && instance.Status.Sleep.Asleep
+	if status.Asleep && !wasAsleep {
+		log.Info(fmt.Sprintf("Going to sleep until %s", next.Format(time.RFC3339)))
+		r.recorder.Eventf(instance, "Normal", "AppService Asleep", "Going to sleep until %s", next.Format(time.RFC3339))
+	} else if !status.Asleep && wasAsleep {
+		log.Info("Waking up")
+		r.recorder.Eventf(instance, "Normal", "AppService Awake", "Waking up")
+	}
+	instance.Status.Sleep = status
+	return nil
+}
+
+// getWakeUpUntil returns the time of the wake-up annotation, nil if there's none
+func getWakeUpUntil(instance *gramolav1alpha1.AppService) (*time.Time, error) {
+	value, ok := instance.Annotations[gramolav1alpha1.WakeUpUntilAnnotation]
+	if !ok || len(value) == 0 {
+		return nil, nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s annotation %s, an RFC 3339 time is expected: %v", gramolav1alpha1.WakeUpUntilAnnotation, value, err)
+	}
+	return &until, nil
+}
+
+// sleepRequeueAfter returns the time until the AppService goes to sleep or wakes up next, zero if it has no sleep schedule
+func sleepRequeueAfter(instance *gramolav1alpha1.AppService) time.Duration {
+	if instance.Status.Sleep == nil {
+		return 0
+	}
+	next := instance.Status.Sleep.NextTransition.Time
+	if instance.Status.Sleep.WakeUpUntil != nil && instance.Status.Sleep.WakeUpUntil.Before(&instance.Status.Sleep.NextTransition) {
+		next = instance.Status.Sleep.WakeUpUntil.Time
+	}
+	after := time.Until(next)
+	if after < time.Second {
+		after = time.Second
+	}
+	return after
+}
@@ -0,0 +1,201 @@
+package appserviceclone
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_database "github.com/redhat/gramola-operator/pkg/database"
+	util "github.com/redhat/gramola-operator/pkg/util"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Best practices
+const controllerName = "controller-appserviceclone"
+
+const (
+	errorUnableToUpdateStatus = "Unable to update status"
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new AppServiceClone Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileAppServiceClone{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor(controllerName)}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource AppServiceClone
+	err = c.Watch(&source.Kind{Type: &gramolav1alpha1.AppServiceClone{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileAppServiceClone implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAppServiceClone{}
+
+// ReconcileAppServiceClone reconciles a AppServiceClone object
+type ReconcileAppServiceClone struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+	// Best practices...
+	recorder record.EventRecorder
+}
+
+// Reconcile copies the events of the source AppService into the target one once, masking columns on the way,
+// and records the lineage in the status of the target
+func (r *ReconcileAppServiceClone) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling AppServiceClone")
+
+	// Fetch the AppServiceClone instance
+	instance := &gramolav1alpha1.AppServiceClone{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Clones run only once
+	if instance.Status.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	if instance.Status.StartTime.IsZero() {
+		instance.Status.StartTime = metav1.Now()
+		instance.Status.Phase = gramolav1alpha1.DataTransferPhasePending
+	}
+
+	// Each AppService has its own events database, even in the same namespace
+	sourceNamespace := util.NVL(instance.Spec.Source.Namespace, instance.Namespace)
+	if sourceNamespace == instance.Namespace && instance.Spec.Source.Name == instance.Spec.Target {
+		return r.manageFailure(instance, fmt.Errorf("Source and target are the same AppService %s", instance.Spec.Target))
+	}
+
+	masks := map[string]string{}
+	maskedColumns := []string{}
+	for i := range instance.Spec.MaskingRules {
+		rule := &instance.Spec.MaskingRules[i]
+		expression, err := _database.GetMaskExpression(rule)
+		if err != nil {
+			return r.manageFailure(instance, err)
+		}
+		masks[rule.Column] = expression
+		maskedColumns = append(maskedColumns, rule.Column)
+	}
+
+	sourceAppService := &gramolav1alpha1.AppService{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.Source.Name, Namespace: sourceNamespace}, sourceAppService); err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Unable to get source AppService %s/%s: %v", sourceNamespace, instance.Spec.Source.Name, err))
+	}
+	targetAppService := &gramolav1alpha1.AppService{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.Target, Namespace: instance.Namespace}, targetAppService); err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Unable to get target AppService %s: %v", instance.Spec.Target, err))
+	}
+
+	sourcePod, err := _database.GetReadyEventsDatabasePod(r.client, sourceAppService)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	targetPod, err := _database.GetReadyEventsDatabasePod(r.client, targetAppService)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if sourcePod == nil || targetPod == nil {
+		instance.Status.Message = "Waiting for the source and target events databases to be ready"
+		return r.manageProgress(instance, 10*time.Second)
+	}
+
+	// Masking happens in the source database, unmasked values never leave it
+	data, stderr, err := _database.StreamRemoteCommand(sourcePod, _database.ExportMaskedEventsCommand(gramolav1alpha1.DataFormatCSV, "", masks), nil)
+	if err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Export from %s failed %v: %s", sourceNamespace, err, stderr))
+	}
+	rows, err := _database.CountRows(gramolav1alpha1.DataFormatCSV, data)
+	if err != nil {
+		return r.manageFailure(instance, err)
+	}
+
+	if _, stderr, err := _database.StreamRemoteCommand(targetPod, _database.ImportEventsCommand(gramolav1alpha1.DataFormatCSV, gramolav1alpha1.DataImportModeReplace, ""), strings.NewReader(data)); err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Import into %s failed %v: %s", instance.Namespace, err, stderr))
+	}
+
+	targetAppService.Status.Lineage = &gramolav1alpha1.Lineage{
+		SourceNamespace: sourceNamespace,
+		SourceName:      sourceAppService.Name,
+		Clone:           instance.Name,
+		ClonedAt:        metav1.Now(),
+		MaskedColumns:   maskedColumns,
+	}
+	if err := r.client.Status().Update(context.TODO(), targetAppService); err != nil {
+		log.Error(err, errorUnableToUpdateStatus, "appservice", targetAppService.Name)
+		return r.manageFailure(instance, fmt.Errorf("Events were copied but the lineage of %s could not be recorded: %v", targetAppService.Name, err))
+	}
+
+	instance.Status.Rows = rows
+	return r.manageSuccess(instance, fmt.Sprintf("Cloned %d events from %s/%s into %s masking [%s]", rows, sourceNamespace, sourceAppService.Name, targetAppService.Name, strings.Join(maskedColumns, ", ")))
+}
+
+func (r *ReconcileAppServiceClone) manageProgress(instance *gramolav1alpha1.AppServiceClone, requeueAfter time.Duration) (reconcile.Result, error) {
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, errorUnableToUpdateStatus)
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *ReconcileAppServiceClone) manageSuccess(instance *gramolav1alpha1.AppServiceClone, message string) (reconcile.Result, error) {
+	log.Info(message, "clone", instance.Name)
+	r.recorder.Event(instance, "Normal", "Cloned", message)
+	instance.Status.Phase = gramolav1alpha1.DataTransferPhaseSucceeded
+	instance.Status.CompletionTime = metav1.Now()
+	instance.Status.Message = message
+	return r.manageProgress(instance, 0)
+}
+
+func (r *ReconcileAppServiceClone) manageFailure(instance *gramolav1alpha1.AppServiceClone, issue error) (reconcile.Result, error) {
+	log.Error(issue, "Clone failed", "clone", instance.Name)
+	r.recorder.Event(instance, "Warning", "CloneFailed", issue.Error())
+	instance.Status.Phase = gramolav1alpha1.DataTransferPhaseFailed
+	instance.Status.CompletionTime = metav1.Now()
+	instance.Status.Message = issue.Error()
+	return r.manageProgress(instance, 0)
+}
@@ -0,0 +1,265 @@
+package appservicedataexport
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_database "github.com/redhat/gramola-operator/pkg/database"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	util "github.com/redhat/gramola-operator/pkg/util"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Best practices
+const controllerName = "controller-appservicedataexport"
+
+const (
+	errorUnableToUpdateStatus = "Unable to update status"
+)
+
+// MaxConfigMapDataSize is the maximum size of the data exported to a ConfigMap, objects are limited to 1MiB
+const MaxConfigMapDataSize = 1000 * 1000
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new AppServiceDataExport Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileAppServiceDataExport{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor(controllerName)}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource AppServiceDataExport
+	err = c.Watch(&source.Kind{Type: &gramolav1alpha1.AppServiceDataExport{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource Jobs and requeue the owner AppServiceDataExport
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &gramolav1alpha1.AppServiceDataExport{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileAppServiceDataExport implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAppServiceDataExport{}
+
+// ReconcileAppServiceDataExport reconciles a AppServiceDataExport object
+type ReconcileAppServiceDataExport struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+	// Best practices...
+	recorder record.EventRecorder
+}
+
+// Reconcile exports the events of an AppService once, to a ConfigMap directly or to a PersistentVolumeClaim through a Job
+func (r *ReconcileAppServiceDataExport) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling AppServiceDataExport")
+
+	// Fetch the AppServiceDataExport instance
+	instance := &gramolav1alpha1.AppServiceDataExport{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Exports run only once
+	if instance.Status.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	if instance.Status.StartTime.IsZero() {
+		instance.Status.StartTime = metav1.Now()
+		instance.Status.Phase = gramolav1alpha1.DataTransferPhasePending
+	}
+
+	if err := instance.Spec.Storage.Validate(); err != nil {
+		return r.manageFailure(instance, err)
+	}
+
+	appService := &gramolav1alpha1.AppService{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.AppService, Namespace: instance.Namespace}, appService); err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Unable to get AppService %s: %v", instance.Spec.AppService, err))
+	}
+
+	pod, err := _database.GetReadyEventsDatabasePod(r.client, appService)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pod == nil && len(instance.Status.Job) == 0 {
+		instance.Status.Message = "Waiting for the events database to be ready"
+		return r.manageProgress(instance, 10*time.Second)
+	}
+
+	// The AppService makes the events database read-only while the backup runs
+	if len(instance.Status.Job) == 0 && appService.Spec.GetReadOnlyMode() == gramolav1alpha1.ReadOnlyModeDuringOperations {
+		if condition := appService.Status.GetCondition(gramolav1alpha1.AppServiceConditionTypeReadOnly); condition == nil ||
+			condition.Status != gramolav1alpha1.AppServiceConditionStatusTrue {
+			instance.Status.Message = "Waiting for the events database to be read-only"
+			return r.manageProgress(instance, 5*time.Second)
+		}
+	}
+
+	if instance.Spec.Storage.ConfigMap != nil {
+		return r.exportToConfigMap(instance, appService, pod)
+	}
+	return r.exportToPersistentVolumeClaim(instance, appService)
+}
+
+// exportToConfigMap runs the export in the database pod and stores its output in a ConfigMap
+func (r *ReconcileAppServiceDataExport) exportToConfigMap(instance *gramolav1alpha1.AppServiceDataExport, appService *gramolav1alpha1.AppService, pod *corev1.Pod) (reconcile.Result, error) {
+	storage := instance.Spec.Storage.ConfigMap
+	key := util.NVL(storage.Key, _database.DefaultDataFileName(instance.Spec.Format))
+
+	data, stderr, err := _database.StreamRemoteCommand(pod, _database.ExportEventsCommand(instance.Spec.Format, ""), nil)
+	if err != nil {
+		return r.manageFailure(instance, fmt.Errorf("%v: %s", err, stderr))
+	}
+	if len(data) > MaxConfigMapDataSize {
+		return r.manageFailure(instance, fmt.Errorf("Exported data is %d bytes long, too big for a ConfigMap, use a PersistentVolumeClaim instead", len(data)))
+	}
+	rows, err := _database.CountRows(instance.Spec.Format, data)
+	if err != nil {
+		return r.manageFailure(instance, err)
+	}
+
+	configMap := _deployment.NewConfigMapFromData(appService, storage.Name, instance.Namespace, map[string]string{key: data})
+	if err := r.client.Create(context.TODO(), configMap); err != nil {
+		if !k8s_errors.IsAlreadyExists(err) {
+			return r.manageFailure(instance, err)
+		}
+		from := &corev1.ConfigMap{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, from); err != nil {
+			return r.manageFailure(instance, err)
+		}
+		patch := client.MergeFrom(from.DeepCopy())
+		if from.Data == nil {
+			from.Data = map[string]string{}
+		}
+		from.Data[key] = data
+		if err := r.client.Patch(context.TODO(), from, patch); err != nil {
+			return r.manageFailure(instance, err)
+		}
+	}
+
+	instance.Status.Rows = rows
+	return r.manageSuccess(instance, fmt.Sprintf("Exported %d events to ConfigMap %s key %s", rows, storage.Name, key))
+}
+
+// exportToPersistentVolumeClaim runs the export in a Job that mounts the PersistentVolumeClaim
+func (r *ReconcileAppServiceDataExport) exportToPersistentVolumeClaim(instance *gramolav1alpha1.AppServiceDataExport, appService *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	storage := instance.Spec.Storage.PersistentVolumeClaim
+	file := path.Join(_deployment.EventsDatabaseJobDataMountPath, util.NVL(storage.Path, _database.DefaultDataFileName(instance.Spec.Format)))
+
+	job := &batchv1.Job{}
+	jobName := instance.Name + "-export"
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: instance.Namespace}, job); err != nil {
+		if !k8s_errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		job, err = _deployment.NewEventsDatabaseJob(appService, instance, jobName, _database.ExportEventsCommand(instance.Spec.Format, file), storage.ClaimName, r.scheme)
+		if err != nil {
+			return r.manageFailure(instance, err)
+		}
+		if err := r.client.Create(context.TODO(), job); err != nil {
+			return r.manageFailure(instance, err)
+		}
+		log.Info(fmt.Sprintf("Created %s Job", job.Name))
+		r.recorder.Eventf(instance, "Normal", "Job Created", "Created %s Job", job.Name)
+
+		instance.Status.Phase = gramolav1alpha1.DataTransferPhaseRunning
+		instance.Status.Job = job.Name
+		instance.Status.Message = fmt.Sprintf("Exporting events to %s in PersistentVolumeClaim %s", file, storage.ClaimName)
+		return r.manageProgress(instance, 0)
+	}
+
+	result, err := _database.GetJobResult(r.client, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !result.Finished {
+		// Job events will requeue the request
+		return reconcile.Result{}, nil
+	}
+	if !result.Succeeded {
+		return r.manageFailure(instance, fmt.Errorf("Job %s failed: %s", job.Name, result.Message))
+	}
+
+	rows, err := _database.ParseRowCount(result.Message)
+	if err != nil {
+		return r.manageFailure(instance, err)
+	}
+	instance.Status.Rows = rows
+	return r.manageSuccess(instance, fmt.Sprintf("Exported %d events to %s in PersistentVolumeClaim %s", rows, file, storage.ClaimName))
+}
+
+func (r *ReconcileAppServiceDataExport) manageProgress(instance *gramolav1alpha1.AppServiceDataExport, requeueAfter time.Duration) (reconcile.Result, error) {
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, errorUnableToUpdateStatus)
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *ReconcileAppServiceDataExport) manageSuccess(instance *gramolav1alpha1.AppServiceDataExport, message string) (reconcile.Result, error) {
+	log.Info(message, "export", instance.Name)
+	r.recorder.Event(instance, "Normal", "Exported", message)
+	instance.Status.Phase = gramolav1alpha1.DataTransferPhaseSucceeded
+	instance.Status.CompletionTime = metav1.Now()
+	instance.Status.Message = message
+	return r.manageProgress(instance, 0)
+}
+
+func (r *ReconcileAppServiceDataExport) manageFailure(instance *gramolav1alpha1.AppServiceDataExport, issue error) (reconcile.Result, error) {
+	log.Error(issue, "Export failed", "export", instance.Name)
+	r.recorder.Event(instance, "Warning", "ExportFailed", issue.Error())
+	instance.Status.Phase = gramolav1alpha1.DataTransferPhaseFailed
+	instance.Status.CompletionTime = metav1.Now()
+	instance.Status.Message = issue.Error()
+	return r.manageProgress(instance, 0)
+}
@@ -0,0 +1,304 @@
+package appservicedataimport
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_database "github.com/redhat/gramola-operator/pkg/database"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	_maintenance "github.com/redhat/gramola-operator/pkg/maintenance"
+	util "github.com/redhat/gramola-operator/pkg/util"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Best practices
+const controllerName = "controller-appservicedataimport"
+
+const (
+	errorUnableToUpdateStatus = "Unable to update status"
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new AppServiceDataImport Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileAppServiceDataImport{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor(controllerName)}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource AppServiceDataImport
+	err = c.Watch(&source.Kind{Type: &gramolav1alpha1.AppServiceDataImport{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource Jobs and requeue the owner AppServiceDataImport
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &gramolav1alpha1.AppServiceDataImport{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileAppServiceDataImport implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAppServiceDataImport{}
+
+// ReconcileAppServiceDataImport reconciles a AppServiceDataImport object
+type ReconcileAppServiceDataImport struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+	// Best practices...
+	recorder record.EventRecorder
+}
+
+// Reconcile imports events into an AppService once, from a ConfigMap directly or from a PersistentVolumeClaim through a Job
+func (r *ReconcileAppServiceDataImport) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling AppServiceDataImport")
+
+	// Fetch the AppServiceDataImport instance
+	instance := &gramolav1alpha1.AppServiceDataImport{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// Imports run only once, a restore hides the maintenance page once finished
+	if instance.Status.IsFinished() {
+		return reconcile.Result{}, r.hideMaintenancePage(instance)
+	}
+
+	if instance.Status.StartTime.IsZero() {
+		instance.Status.StartTime = metav1.Now()
+		instance.Status.Phase = gramolav1alpha1.DataTransferPhasePending
+	}
+
+	if err := instance.Spec.Storage.Validate(); err != nil {
+		return r.manageFailure(instance, err)
+	}
+
+	appService := &gramolav1alpha1.AppService{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.AppService, Namespace: instance.Namespace}, appService); err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Unable to get AppService %s: %v", instance.Spec.AppService, err))
+	}
+
+	pod, err := _database.GetReadyEventsDatabasePod(r.client, appService)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pod == nil && len(instance.Status.Job) == 0 {
+		instance.Status.Message = "Waiting for the events database to be ready"
+		return r.manageProgress(instance, 10*time.Second)
+	}
+
+	var result reconcile.Result
+	if instance.Spec.Storage.ConfigMap != nil {
+		result, err = r.importFromConfigMap(instance, appService, pod)
+	} else {
+		result, err = r.importFromPersistentVolumeClaim(instance, appService)
+	}
+	if err == nil && instance.Status.IsFinished() {
+		err = r.hideMaintenancePage(instance)
+	}
+	return result, err
+}
+
+// importFromConfigMap streams the data in a ConfigMap to the import run in the database pod
+func (r *ReconcileAppServiceDataImport) importFromConfigMap(instance *gramolav1alpha1.AppServiceDataImport, appService *gramolav1alpha1.AppService, pod *corev1.Pod) (reconcile.Result, error) {
+	storage := instance.Spec.Storage.ConfigMap
+	key := util.NVL(storage.Key, _database.DefaultDataFileName(instance.Spec.Format))
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: storage.Name, Namespace: instance.Namespace}, configMap); err != nil {
+		return r.manageFailure(instance, fmt.Errorf("Unable to get ConfigMap %s: %v", storage.Name, err))
+	}
+	data, ok := configMap.Data[key]
+	if !ok {
+		binaryData, ok := configMap.BinaryData[key]
+		if !ok {
+			return r.manageFailure(instance, fmt.Errorf("ConfigMap %s has no key %s", storage.Name, key))
+		}
+		data = string(binaryData)
+	}
+
+	if err := r.showMaintenancePage(instance, appService); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	command := _database.ImportEventsCommand(instance.Spec.Format, getMode(instance), "")
+	out, stderr, err := _database.StreamRemoteCommand(pod, command, strings.NewReader(data))
+	if err != nil {
+		return r.manageFailure(instance, fmt.Errorf("%v: %s", err, stderr))
+	}
+	rows, err := _database.ParseRowCount(out)
+	if err != nil {
+		return r.manageFailure(instance, err)
+	}
+
+	instance.Status.Rows = rows
+	return r.manageSuccess(instance, fmt.Sprintf("Imported (%s) %d events from ConfigMap %s key %s", getMode(instance), rows, storage.Name, key))
+}
+
+// importFromPersistentVolumeClaim runs the import in a Job that mounts the PersistentVolumeClaim
+func (r *ReconcileAppServiceDataImport) importFromPersistentVolumeClaim(instance *gramolav1alpha1.AppServiceDataImport, appService *gramolav1alpha1.AppService) (reconcile.Result, error) {
+	storage := instance.Spec.Storage.PersistentVolumeClaim
+	file := path.Join(_deployment.EventsDatabaseJobDataMountPath, util.NVL(storage.Path, _database.DefaultDataFileName(instance.Spec.Format)))
+
+	job := &batchv1.Job{}
+	jobName := instance.Name + "-import"
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: instance.Namespace}, job); err != nil {
+		if !k8s_errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		if err := r.showMaintenancePage(instance, appService); err != nil {
+			return reconcile.Result{}, err
+		}
+		command := _database.ImportEventsCommand(instance.Spec.Format, getMode(instance), file)
+		job, err = _deployment.NewEventsDatabaseJob(appService, instance, jobName, command, storage.ClaimName, r.scheme)
+		if err != nil {
+			return r.manageFailure(instance, err)
+		}
+		if err := r.client.Create(context.TODO(), job); err != nil {
+			return r.manageFailure(instance, err)
+		}
+		log.Info(fmt.Sprintf("Created %s Job", job.Name))
+		r.recorder.Eventf(instance, "Normal", "Job Created", "Created %s Job", job.Name)
+
+		instance.Status.Phase = gramolav1alpha1.DataTransferPhaseRunning
+		instance.Status.Job = job.Name
+		instance.Status.Message = fmt.Sprintf("Importing events from %s in PersistentVolumeClaim %s", file, storage.ClaimName)
+		return r.manageProgress(instance, 0)
+	}
+
+	result, err := _database.GetJobResult(r.client, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !result.Finished {
+		// Job events will requeue the request
+		return reconcile.Result{}, nil
+	}
+	if !result.Succeeded {
+		return r.manageFailure(instance, fmt.Errorf("Job %s failed: %s", job.Name, result.Message))
+	}
+
+	rows, err := _database.ParseRowCount(result.Message)
+	if err != nil {
+		return r.manageFailure(instance, err)
+	}
+	instance.Status.Rows = rows
+	return r.manageSuccess(instance, fmt.Sprintf("Imported (%s) %d events from %s in PersistentVolumeClaim %s", getMode(instance), rows, file, storage.ClaimName))
+}
+
+// getMode returns the import mode, Upsert by default
+func getMode(instance *gramolav1alpha1.AppServiceDataImport) gramolav1alpha1.DataImportMode {
+	if len(instance.Spec.Mode) == 0 {
+		return gramolav1alpha1.DataImportModeUpsert
+	}
+	return instance.Spec.Mode
+}
+
+// showMaintenancePage points the frontend Route of the AppService to the maintenance page while a restore (Replace) runs
+func (r *ReconcileAppServiceDataImport) showMaintenancePage(instance *gramolav1alpha1.AppServiceDataImport, appService *gramolav1alpha1.AppService) error {
+	if getMode(instance) != gramolav1alpha1.DataImportModeReplace {
+		return nil
+	}
+	shown, err := _maintenance.EnablePage(r.client, r.scheme, appService, gramolav1alpha1.MaintenancePageReasonRestore, instance.Name)
+	if err != nil || !shown {
+		return err
+	}
+	log.Info(fmt.Sprintf("Showing maintenance page of %s during restore", appService.Name), "import", instance.Name)
+	r.recorder.Eventf(instance, "Normal", "Maintenance Page Shown", "Frontend Route of %s points to %s during restore", appService.Name, _deployment.GetObjectName(appService, _deployment.MaintenancePageName))
+	return nil
+}
+
+// hideMaintenancePage points the frontend Route of the AppService back to the frontend if the restore showed the maintenance page
+func (r *ReconcileAppServiceDataImport) hideMaintenancePage(instance *gramolav1alpha1.AppServiceDataImport) error {
+	if getMode(instance) != gramolav1alpha1.DataImportModeReplace {
+		return nil
+	}
+	appService := &gramolav1alpha1.AppService{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.AppService, Namespace: instance.Namespace}, appService); err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	hidden, err := _maintenance.DisablePage(r.client, appService, gramolav1alpha1.MaintenancePageReasonRestore, instance.Name)
+	if err != nil || !hidden {
+		return err
+	}
+	log.Info(fmt.Sprintf("Hiding maintenance page of %s after restore", appService.Name), "import", instance.Name)
+	r.recorder.Eventf(instance, "Normal", "Maintenance Page Hidden", "Frontend Route of %s points to %s after restore", appService.Name, _deployment.GetObjectName(appService, _deployment.FrontendServiceName))
+	return nil
+}
+
+func (r *ReconcileAppServiceDataImport) manageProgress(instance *gramolav1alpha1.AppServiceDataImport, requeueAfter time.Duration) (reconcile.Result, error) {
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, errorUnableToUpdateStatus)
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *ReconcileAppServiceDataImport) manageSuccess(instance *gramolav1alpha1.AppServiceDataImport, message string) (reconcile.Result, error) {
+	log.Info(message, "import", instance.Name)
+	r.recorder.Event(instance, "Normal", "Imported", message)
+	instance.Status.Phase = gramolav1alpha1.DataTransferPhaseSucceeded
+	instance.Status.CompletionTime = metav1.Now()
+	instance.Status.Message = message
+	return r.manageProgress(instance, 0)
+}
+
+func (r *ReconcileAppServiceDataImport) manageFailure(instance *gramolav1alpha1.AppServiceDataImport, issue error) (reconcile.Result, error) {
+	log.Error(issue, "Import failed", "import", instance.Name)
+	r.recorder.Event(instance, "Warning", "ImportFailed", issue.Error())
+	instance.Status.Phase = gramolav1alpha1.DataTransferPhaseFailed
+	instance.Status.CompletionTime = metav1.Now()
+	instance.Status.Message = issue.Error()
+	return r.manageProgress(instance, 0)
+}
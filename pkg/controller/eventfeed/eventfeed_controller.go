@@ -0,0 +1,215 @@
+package eventfeed
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	"github.com/redhat/gramola-operator/pkg/feed"
+	"github.com/redhat/gramola-operator/pkg/gateway"
+	util "github.com/redhat/gramola-operator/pkg/util"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Best practices
+const controllerName = "controller-eventfeed"
+
+// DefaultSchedule is used when the EventFeed has no schedule, every hour
+const DefaultSchedule = "0 * * * *"
+
+const (
+	errorUnableToUpdateStatus = "Unable to update status"
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new EventFeed Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileEventFeed{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor(controllerName)}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource EventFeed, status updates don't change the generation
+	err = c.Watch(&source.Kind{Type: &gramolav1alpha1.EventFeed{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileEventFeed implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileEventFeed{}
+
+// ReconcileEventFeed reconciles a EventFeed object
+type ReconcileEventFeed struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+	// Best practices...
+	recorder record.EventRecorder
+}
+
+// Reconcile imports the feed of an EventFeed object if it is due according to its schedule
+// or its spec has changed, and requeues the request for the next scheduled import
+func (r *ReconcileEventFeed) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling EventFeed")
+
+	// Fetch the EventFeed instance
+	instance := &gramolav1alpha1.EventFeed{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	schedule, err := cron.ParseStandard(util.NVL(instance.Spec.Schedule, DefaultSchedule))
+	if err != nil {
+		// Nothing to do until the spec is fixed
+		instance.Status.LastError = fmt.Sprintf("Invalid schedule %s: %v", instance.Spec.Schedule, err)
+		return reconcile.Result{}, r.updateStatus(instance)
+	}
+
+	now := time.Now()
+	lastFetch := instance.Status.LastFetchTime
+	specChanged := instance.Status.ObservedGeneration != instance.Generation
+	if !specChanged && !lastFetch.IsZero() {
+		if next := schedule.Next(lastFetch.Time); next.After(now) {
+			return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	r.importFeed(instance)
+	instance.Status.ObservedGeneration = instance.Generation
+	if err := r.updateStatus(instance); err != nil {
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+}
+
+// importFeed fetches the feed and upserts its entries through the gateway of the target AppService,
+// counters and errors are recorded in the status of instance
+func (r *ReconcileEventFeed) importFeed(instance *gramolav1alpha1.EventFeed) {
+	status := &instance.Status
+	status.LastFetchTime = metav1.Now()
+	status.Entries, status.Created, status.Updated, status.Unchanged, status.Failed = 0, 0, 0, 0, 0
+
+	fail := func(err error) {
+		log.Error(err, "Error importing feed", "feed", instance.Name)
+		status.LastError = err.Error()
+		r.recorder.Event(instance, "Warning", "ImportError", err.Error())
+	}
+
+	appService := &gramolav1alpha1.AppService{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.AppService, Namespace: instance.Namespace}, appService); err != nil {
+		fail(fmt.Errorf("Unable to get AppService %s: %v", instance.Spec.AppService, err))
+		return
+	}
+
+	data, err := feed.Fetch(nil, instance.Spec.URL)
+	if err != nil {
+		fail(err)
+		return
+	}
+	records, err := feed.Parse(instance.Spec.Format, data, instance.Spec.ItemsPath)
+	if err != nil {
+		fail(fmt.Errorf("Unable to parse feed %s: %v", instance.Spec.URL, err))
+		return
+	}
+	status.Entries = int32(len(records))
+
+	events := gateway.NewEventsClient(GatewayURL(appService))
+	current, err := events.List()
+	if err != nil {
+		fail(err)
+		return
+	}
+	existing := gateway.IndexByKey(current)
+
+	mapping := feed.FieldMapping(instance.Spec.Format, instance.Spec.FieldMapping)
+	var lastErr error
+	for _, record := range records {
+		event, err := feed.MapRecord(record, mapping)
+		if err != nil {
+			status.Failed++
+			lastErr = err
+			continue
+		}
+		result, err := events.Upsert(existing, event)
+		if err != nil {
+			status.Failed++
+			lastErr = err
+			continue
+		}
+		switch result {
+		case gateway.UpsertCreated:
+			status.Created++
+		case gateway.UpsertUpdated:
+			status.Updated++
+		default:
+			status.Unchanged++
+		}
+	}
+	status.TotalImported += int64(status.Created + status.Updated)
+
+	if lastErr != nil {
+		fail(fmt.Errorf("%d of %d entries failed, last error: %v", status.Failed, status.Entries, lastErr))
+		return
+	}
+
+	status.LastError = ""
+	status.LastSuccessfulFetchTime = status.LastFetchTime
+	log.Info(fmt.Sprintf("Imported feed %s: %d created, %d updated, %d unchanged", instance.Name, status.Created, status.Updated, status.Unchanged))
+	r.recorder.Eventf(instance, "Normal", "Imported", "Imported %d entries: %d created, %d updated, %d unchanged", status.Entries, status.Created, status.Updated, status.Unchanged)
+}
+
+func (r *ReconcileEventFeed) updateStatus(instance *gramolav1alpha1.EventFeed) error {
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, errorUnableToUpdateStatus, "feed", instance.Name)
+		return err
+	}
+	return nil
+}
+
+// GatewayURL returns the in-cluster URL of the gateway of an AppService
+func GatewayURL(appService *gramolav1alpha1.AppService) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", _deployment.GetObjectName(appService, _deployment.GatewayServiceName), appService.Namespace, _deployment.GatewayServicePort)
+}
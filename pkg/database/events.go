@@ -0,0 +1,226 @@
+package database
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+)
+
+// PsqlCommand runs psql against the events database, it relies on the environment of the
+// events database container (or an equivalent one in a Job)
+const PsqlCommand = "psql -v ON_ERROR_STOP=1 -q -A -t -U $POSTGRESQL_USER $POSTGRESQL_DATABASE"
+
+// EventsTable is the table holding the events
+const EventsTable = "public.event"
+
+// EventColumns are the columns of the events table in export order
+var EventColumns = []string{
+	"id",
+	"address",
+	"artist",
+	"city",
+	"country",
+	"date",
+	"start_date",
+	"end_date",
+	"description",
+	"end_time",
+	"image",
+	"location",
+	"name",
+	"province",
+	"start_time",
+}
+
+// Options of COPY for each data format, JSON Lines are copied as single column CSV whose
+// quote and delimiter never show up in JSON so that nothing is escaped
+var copyOptions = map[gramolav1alpha1.DataFormat]string{
+	gramolav1alpha1.DataFormatCSV:       "WITH (FORMAT csv, HEADER true)",
+	gramolav1alpha1.DataFormatJSONLines: "WITH (FORMAT csv, QUOTE e'\\x01', DELIMITER e'\\x02')",
+}
+
+// DefaultDataFileName returns the default file name (or ConfigMap key) for a data format
+func DefaultDataFileName(format gramolav1alpha1.DataFormat) string {
+	if format == gramolav1alpha1.DataFormatCSV {
+		return "events.csv"
+	}
+	return "events.jsonl"
+}
+
+// ExportEventsCommand returns a shell command that exports the events in format to file, or to stdout if file is empty.
+// When exporting to a file the number of exported rows is printed last
+func ExportEventsCommand(format gramolav1alpha1.DataFormat, file string) string {
+	return ExportMaskedEventsCommand(format, file, nil)
+}
+
+// ExportMaskedEventsCommand works as ExportEventsCommand but columns in masks are replaced by the SQL expressions they map to
+func ExportMaskedEventsCommand(format gramolav1alpha1.DataFormat, file string, masks map[string]string) string {
+	columns := []string{}
+	for _, column := range EventColumns {
+		if expression, ok := masks[column]; ok {
+			columns = append(columns, fmt.Sprintf("%s AS %s", expression, column))
+		} else {
+			columns = append(columns, column)
+		}
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY id", strings.Join(columns, ", "), EventsTable)
+	if format == gramolav1alpha1.DataFormatJSONLines {
+		query = fmt.Sprintf("SELECT row_to_json(e) FROM (%s) e", query)
+	}
+
+	target := "pstdout"
+	if len(file) > 0 {
+		target = "'" + file + "'"
+	}
+
+	commands := []string{
+		// Rows and count come from the same snapshot
+		"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ",
+		fmt.Sprintf("\\copy (%s) TO %s %s", query, target, copyOptions[format]),
+	}
+	if len(file) > 0 {
+		commands = append(commands, fmt.Sprintf("SELECT count(*) FROM %s", EventsTable))
+	}
+
+	return psql(commands)
+}
+
+// validFixedValue matches the values accepted by the Fixed masking strategy, they end up in SQL run through a shell
+var validFixedValue = regexp.MustCompile(`^[A-Za-z0-9 ._,:@/-]*$`)
+
+// GetMaskExpression returns the SQL expression that masks a column of the events table given a masking rule
+func GetMaskExpression(rule *gramolav1alpha1.MaskingRule) (string, error) {
+	valid := false
+	for _, column := range EventColumns[1:] {
+		valid = valid || column == rule.Column
+	}
+	if !valid {
+		return "", fmt.Errorf("Column %s can't be masked, valid columns are %s", rule.Column, strings.Join(EventColumns[1:], ", "))
+	}
+
+	switch rule.Strategy {
+	case gramolav1alpha1.MaskingStrategyRedact:
+		return fmt.Sprintf("repeat('*', length(%s))", rule.Column), nil
+	case gramolav1alpha1.MaskingStrategyHash:
+		return fmt.Sprintf("md5(%s)", rule.Column), nil
+	case gramolav1alpha1.MaskingStrategyNull:
+		return "NULL", nil
+	case gramolav1alpha1.MaskingStrategyFixed:
+		if !validFixedValue.MatchString(rule.Value) {
+			return "", fmt.Errorf("Invalid value %q for column %s, only letters, digits, spaces and ._,:@/- are allowed", rule.Value, rule.Column)
+		}
+		return fmt.Sprintf("CASE WHEN %s IS NULL THEN NULL ELSE '%s' END", rule.Column, rule.Value), nil
+	}
+	return "", fmt.Errorf("Unsupported masking strategy %s for column %s", rule.Strategy, rule.Column)
+}
+
+// ImportEventsCommand returns a shell command that imports the events in format from file, or from stdin if file is empty,
+// either upserting them by id or replacing all the existing ones. The number of imported rows is printed last
+func ImportEventsCommand(format gramolav1alpha1.DataFormat, mode gramolav1alpha1.DataImportMode, file string) string {
+	columns := strings.Join(EventColumns, ", ")
+	source := "pstdin"
+	if len(file) > 0 {
+		source = "'" + file + "'"
+	}
+
+	commands := []string{
+		fmt.Sprintf("CREATE TEMP TABLE event_import (LIKE %s) ON COMMIT DROP", EventsTable),
+	}
+	if format == gramolav1alpha1.DataFormatJSONLines {
+		commands = append(commands,
+			"CREATE TEMP TABLE event_import_json (doc text) ON COMMIT DROP",
+			fmt.Sprintf("\\copy event_import_json (doc) FROM %s %s", source, copyOptions[format]),
+			fmt.Sprintf("INSERT INTO event_import SELECT r.* FROM event_import_json j, json_populate_record(NULL::%s, j.doc::json) r WHERE trim(j.doc) <> ''", EventsTable),
+		)
+	} else {
+		commands = append(commands,
+			fmt.Sprintf("\\copy event_import (%s) FROM %s %s", columns, source, copyOptions[format]),
+		)
+	}
+
+	if mode == gramolav1alpha1.DataImportModeReplace {
+		commands = append(commands, fmt.Sprintf("DELETE FROM %s", EventsTable))
+	}
+
+	updates := []string{}
+	for _, column := range EventColumns[1:] {
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+	}
+	commands = append(commands,
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM event_import ON CONFLICT (id) DO UPDATE SET %s", EventsTable, columns, columns, strings.Join(updates, ", ")),
+		// Keep new ids generated by the application clear of the imported ones
+		fmt.Sprintf("SELECT setval('public.hibernate_sequence', GREATEST((SELECT COALESCE(MAX(id), 1) FROM %s), (SELECT last_value FROM public.hibernate_sequence)))", EventsTable),
+		"SELECT count(*) FROM event_import",
+	)
+
+	return psql(commands)
+}
+
+// PurgeEventsCommand returns a shell command that deletes the events whose end date (start date or date if empty)
+// is more than days ago and vacuums the table. Dates not in ISO format are never purged. The number of purged rows
+// is printed last
+func PurgeEventsCommand(days int32) string {
+	eventDate := "COALESCE(NULLIF(end_date, ''), NULLIF(start_date, ''), date)"
+	return PsqlCommand +
+		fmt.Sprintf(" -c \"WITH purged AS (DELETE FROM %s WHERE (CASE WHEN %s ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}' THEN substr(%s, 1, 10)::date END) < current_date - %d RETURNING id) SELECT count(*) FROM purged\"", EventsTable, eventDate, eventDate, days) +
+		// VACUUM can't run in a transaction, hence in a command of its own and count is printed first
+		fmt.Sprintf(" -c \"VACUUM ANALYZE %s\"", EventsTable)
+}
+
+// psql returns a psql command line running commands in a single transaction
+func psql(commands []string) string {
+	command := PsqlCommand + " -1"
+	for _, c := range commands {
+		command += " -c \"" + c + "\""
+	}
+	return command
+}
+
+// LastLine returns the last non empty line of output
+func LastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// ParseRowCount parses the row count printed last by the export and import commands
+func ParseRowCount(output string) (int64, error) {
+	count, err := strconv.ParseInt(LastLine(output), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Unexpected output, no row count found: %s", LastLine(output))
+	}
+	return count, nil
+}
+
+// CountRows counts the events in exported data
+func CountRows(format gramolav1alpha1.DataFormat, data string) (int64, error) {
+	if format == gramolav1alpha1.DataFormatJSONLines {
+		count := int64(0)
+		for _, line := range strings.Split(data, "\n") {
+			if len(strings.TrimSpace(line)) > 0 {
+				count++
+			}
+		}
+		return count, nil
+	}
+
+	reader := csv.NewReader(bytes.NewBufferString(data))
+	count := int64(-1) // Header
+	for {
+		if _, err := reader.Read(); err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if count < 0 {
+		count = 0
+	}
+	return count, nil
+}
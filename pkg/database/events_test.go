@@ -0,0 +1,91 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+)
+
+func TestGetMaskExpression(t *testing.T) {
+	tests := []struct {
+		rule     gramolav1alpha1.MaskingRule
+		expected string
+	}{
+		{gramolav1alpha1.MaskingRule{Column: "artist", Strategy: gramolav1alpha1.MaskingStrategyRedact}, "repeat('*', length(artist))"},
+		{gramolav1alpha1.MaskingRule{Column: "address", Strategy: gramolav1alpha1.MaskingStrategyHash}, "md5(address)"},
+		{gramolav1alpha1.MaskingRule{Column: "description", Strategy: gramolav1alpha1.MaskingStrategyNull}, "NULL"},
+		{gramolav1alpha1.MaskingRule{Column: "city", Strategy: gramolav1alpha1.MaskingStrategyFixed, Value: "Somewhere, Earth"},
+			"CASE WHEN city IS NULL THEN NULL ELSE 'Somewhere, Earth' END"},
+		{gramolav1alpha1.MaskingRule{Column: "image", Strategy: gramolav1alpha1.MaskingStrategyFixed, Value: "https://example.com/no-image_1.png"},
+			"CASE WHEN image IS NULL THEN NULL ELSE 'https://example.com/no-image_1.png' END"},
+		{gramolav1alpha1.MaskingRule{Column: "name", Strategy: gramolav1alpha1.MaskingStrategyFixed}, "CASE WHEN name IS NULL THEN NULL ELSE '' END"},
+	}
+	for _, test := range tests {
+		expression, err := GetMaskExpression(&test.rule)
+		if err != nil {
+			t.Errorf("GetMaskExpression of %+v returned an error: %v", test.rule, err)
+		} else if expression != test.expected {
+			t.Errorf("GetMaskExpression of %+v returned %s, expected %s", test.rule, expression, test.expected)
+		}
+	}
+}
+
+func TestGetMaskExpressionRejects(t *testing.T) {
+	fixed := func(value string) gramolav1alpha1.MaskingRule {
+		return gramolav1alpha1.MaskingRule{Column: "city", Strategy: gramolav1alpha1.MaskingStrategyFixed, Value: value}
+	}
+	tests := map[string]gramolav1alpha1.MaskingRule{
+		"single quote":       fixed("O'Brien"),
+		"double quote":       fixed(`say "hi"`),
+		"dollar":             fixed("$POSTGRESQL_PASSWORD"),
+		"command":            fixed("$(id)"),
+		"backtick":           fixed("`id`"),
+		"backslash":          fixed(`\copy`),
+		"semicolon":          fixed("x'; DROP TABLE public.event; --"),
+		"newline":            fixed("a\nb"),
+		"id column":          {Column: "id", Strategy: gramolav1alpha1.MaskingStrategyNull},
+		"unknown column":     {Column: "password", Strategy: gramolav1alpha1.MaskingStrategyNull},
+		"expression column":  {Column: "city || $(id)", Strategy: gramolav1alpha1.MaskingStrategyHash},
+		"unknown strategy":   {Column: "city", Strategy: "Shuffle"},
+		"no strategy":        {Column: "city"},
+		"quoted column name": {Column: `"city"`, Strategy: gramolav1alpha1.MaskingStrategyRedact},
+	}
+	for name, rule := range tests {
+		if expression, err := GetMaskExpression(&rule); err == nil {
+			t.Errorf("GetMaskExpression of a rule with %s returned %s, expected an error", name, expression)
+		}
+	}
+}
+
+func TestExportMaskedEventsCommand(t *testing.T) {
+	masks := map[string]string{}
+	for _, rule := range []gramolav1alpha1.MaskingRule{
+		{Column: "artist", Strategy: gramolav1alpha1.MaskingStrategyHash},
+		{Column: "city", Strategy: gramolav1alpha1.MaskingStrategyFixed, Value: "Nowhere"},
+	} {
+		expression, err := GetMaskExpression(&rule)
+		if err != nil {
+			t.Fatalf("GetMaskExpression of %+v returned an error: %v", rule, err)
+		}
+		masks[rule.Column] = expression
+	}
+
+	command := ExportMaskedEventsCommand(gramolav1alpha1.DataFormatCSV, "/tmp/events.csv", masks)
+	for _, expected := range []string{
+		"SELECT id, address, md5(artist) AS artist, CASE WHEN city IS NULL THEN NULL ELSE 'Nowhere' END AS city, country,",
+		"TO '/tmp/events.csv' WITH (FORMAT csv, HEADER true)",
+	} {
+		if !strings.Contains(command, expected) {
+			t.Errorf("ExportMaskedEventsCommand returned %s, expected it to contain %s", command, expected)
+		}
+	}
+
+	// Masks only ever add these characters, which the double quotes of the shell command take literally
+	unmasked := ExportEventsCommand(gramolav1alpha1.DataFormatCSV, "/tmp/events.csv")
+	for _, c := range []string{"$", "`", "\\", "\""} {
+		if strings.Count(command, c) != strings.Count(unmasked, c) {
+			t.Errorf("ExportMaskedEventsCommand added %s to the command: %s", c, command)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"bytes"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+
+	errors "github.com/pkg/errors"
+)
+
+// ExecuteRemoteCommand executes a remote shell command on the given pod using a TTY
+// returns the output from stdout and stderr (merged into stdout by the TTY)
+func ExecuteRemoteCommand(pod *corev1.Pod, command string) (string, string, error) {
+	return execute(pod, command, nil, true)
+}
+
+// StreamRemoteCommand executes a remote shell command on the given pod streaming stdin (if not nil)
+// returns the output from stdout and stderr untouched, no TTY is used
+func StreamRemoteCommand(pod *corev1.Pod, command string, stdin io.Reader) (string, string, error) {
+	return execute(pod, command, stdin, false)
+}
+
+// restClientConfig returns the config to reach the API server, in cluster or from the kubeconfig
+func restClientConfig() (*rest.Config, error) {
+	kubeCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	)
+	return kubeCfg.ClientConfig()
+}
+
+func execute(pod *corev1.Pod, command string, stdin io.Reader, tty bool) (string, string, error) {
+	restCfg, err := restClientConfig()
+	if err != nil {
+		return "", "", err
+	}
+	coreClient, err := corev1client.NewForConfig(restCfg)
+	if err != nil {
+		return "", "", err
+	}
+
+	buf := &bytes.Buffer{}
+	errBuf := &bytes.Buffer{}
+	request := coreClient.RESTClient().
+		Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command: []string{"/bin/bash", "-c", command},
+			Stdin:   stdin != nil,
+			Stdout:  true,
+			Stderr:  true,
+			TTY:     tty,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(restCfg, "POST", request.URL())
+	if err != nil {
+		return "", "", err
+	}
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: buf,
+		Stderr: errBuf,
+	})
+	if err != nil {
+		return buf.String(), errBuf.String(), errors.Wrapf(err, "Failed executing command %s on %v/%v", command, pod.Namespace, pod.Name)
+	}
+
+	return buf.String(), errBuf.String(), nil
+}
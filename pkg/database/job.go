@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// JobResult tells how a Job built by NewEventsDatabaseJob ended
+type JobResult struct {
+	// Finished is true if the Job completed or failed
+	Finished bool
+	// Succeeded is true if the Job completed
+	Succeeded bool
+	// Message is the termination message of the last pod, the last line printed by the command
+	Message string
+}
+
+// GetJobResult returns the result of a Job given the termination messages of its pods
+func GetJobResult(c client.Client, job *batchv1.Job) (*JobResult, error) {
+	result := &JobResult{}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			result.Finished, result.Succeeded = true, true
+		case batchv1.JobFailed:
+			result.Finished = true
+			result.Message = fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+		}
+	}
+	if !result.Finished {
+		return result, nil
+	}
+
+	podList := &corev1.PodList{}
+	listOps := []client.ListOption{
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	}
+	if err := c.List(context.TODO(), podList, listOps...); err != nil {
+		return nil, err
+	}
+
+	var last *corev1.ContainerStateTerminated
+	for _, pod := range podList.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			terminated := containerStatus.State.Terminated
+			if terminated == nil {
+				continue
+			}
+			if last == nil || terminated.FinishedAt.After(last.FinishedAt.Time) {
+				last = terminated
+			}
+		}
+	}
+	if last != nil && len(last.Message) > 0 {
+		result.Message = LastLine(last.Message)
+	}
+
+	return result, nil
+}
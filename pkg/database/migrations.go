@@ -0,0 +1,155 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	migrations "github.com/redhat/gramola-operator/pkg/migrations"
+
+	corev1 "k8s.io/api/core/v1"
+
+	// PostgreSQL driver
+	_ "github.com/lib/pq"
+)
+
+// Migration is a script, or Go function, run once against the events database
+type Migration struct {
+	// Name of the migration, also its key in the scripts ConfigMap and in the status of the AppService
+	Name string
+	// Version of Gramola's migrations, they run in version order
+	Version string
+	// Script is the SQL to run
+	Script string
+	// Go is the data migration to run instead of a Script
+	Go *migrations.Migration
+	// Builtin is true for Gramola's own migrations, the schema manifest describes the database once they have run
+	Builtin bool
+}
+
+// Digest returns a short digest of the names and contents of migrations, it changes if any of them does
+func Digest(migrations []Migration) string {
+	hash := sha256.New()
+	for _, migration := range migrations {
+		content := migration.Script
+		if migration.Go != nil {
+			content = migration.Go.Version + migration.Go.Description
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00", migration.Name, content)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// MigrationName returns the name of a script of a migration source
+func MigrationName(source string, script string) string {
+	return source + "." + script
+}
+
+// SortedScriptNames returns the keys of scripts ending in .sql in name order
+func SortedScriptNames(scripts map[string]string) []string {
+	names := []string{}
+	for name := range scripts {
+		if strings.HasSuffix(name, ".sql") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadMigrationScripts returns the .sql files in dir of the database pod by file name
+func ReadMigrationScripts(pod *corev1.Pod, dir string) (map[string]string, error) {
+	out, stderr, err := StreamRemoteCommand(pod, fmt.Sprintf("cd %s && ls -1 -- *.sql", dir), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list scripts in %s of pod %s, the database may be restarting %v: %s", dir, pod.Name, err, stderr)
+	}
+
+	scripts := map[string]string{}
+	for _, name := range strings.Split(strings.TrimSpace(out), "\n") {
+		if len(name) == 0 {
+			continue
+		}
+		if strings.Contains(name, "'") {
+			return nil, fmt.Errorf("Invalid script name %s in %s of pod %s", name, dir, pod.Name)
+		}
+		script, stderr, err := StreamRemoteCommand(pod, fmt.Sprintf("cat -- '%s/%s'", dir, name), nil)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read script %s/%s of pod %s %v: %s", dir, name, pod.Name, err, stderr)
+		}
+		scripts[name] = script
+	}
+	return scripts, nil
+}
+
+// RunMigration runs the script of a migration in the database pod, it stops at the first error. It can write
+// even if the database is read-only
+func RunMigration(pod *corev1.Pod, migration *Migration) (string, error) {
+	out, stderr, err := StreamRemoteCommand(pod, ReadWritePsqlCommand, strings.NewReader(migration.Script))
+	if err != nil {
+		return out, fmt.Errorf("Failed executing script %s on %s %v: %s", migration.Name, pod.Name, err, strings.TrimSpace(stderr))
+	}
+	return out, nil
+}
+
+// RunGoMigration runs a Go migration in a transaction through a port forwarded to the database pod. Go migrations are
+// recorded in operator_version by name, not to collide with the version of a script, names already there are not run
+// again. It can write even if the database is read-only
+func RunGoMigration(pod *corev1.Pod, migration *Migration, context *ScriptContext) error {
+	port, stop, err := ForwardPort(pod, _deployment.EventsDatabaseServicePort)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	dsn := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(context.Database.User, context.Database.Password),
+		Host:     fmt.Sprintf("localhost:%d", port),
+		Path:     "/" + context.Database.Name,
+		RawQuery: "sslmode=disable&" + ReadWriteOption,
+	}
+	db, err := sql.Open("postgres", dsn.String())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("Unable to connect to the database of %s: %v", pod.Name, err)
+	}
+	return applyGoMigration(db, migration)
+}
+
+// applyGoMigration runs a Go migration and records it in operator_version by name in the same transaction, unless
+// it's already there
+func applyGoMigration(db *sql.DB, migration *Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE public.operator_version SET run_count = run_count + 1 WHERE version = $1", migration.Name)
+	if err != nil {
+		return err
+	}
+	if updated, err := result.RowsAffected(); err != nil {
+		return err
+	} else if updated > 0 {
+		log.Info(fmt.Sprintf("Migration %s was already applied", migration.Name))
+		return tx.Commit()
+	}
+
+	if err := migration.Go.Run(tx); err != nil {
+		return fmt.Errorf("Failed executing migration %s: %v", migration.Name, err)
+	}
+	if _, err := tx.Exec("INSERT INTO public.operator_version(version, start_time, end_time, script_name, run_count) VALUES ($1, NOW(), clock_timestamp(), $1, 1)", migration.Name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+
+	migrations "github.com/redhat/gramola-operator/pkg/migrations"
+)
+
+// fakeDatabase keeps the run count of the rows of operator_version by version and the statements the migrations
+// ran, changes are only kept if the transaction commits
+type fakeDatabase struct {
+	versions   map[string]int
+	statements []string
+}
+
+func (d *fakeDatabase) copy() *fakeDatabase {
+	c := &fakeDatabase{versions: map[string]int{}, statements: append([]string{}, d.statements...)}
+	for k, v := range d.versions {
+		c.versions[k] = v
+	}
+	return c
+}
+
+var fakeDatabases = map[string]*fakeDatabase{}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{db: fakeDatabases[name]}, nil
+}
+
+type fakeConn struct {
+	db *fakeDatabase
+	tx *fakeDatabase
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.tx = c.db.copy()
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	*c.db = *c.tx
+	c.tx = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.tx = nil
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.tx
+	switch {
+	case strings.HasPrefix(s.query, "UPDATE public.operator_version"):
+		name := args[0].(string)
+		if _, ok := db.versions[name]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		db.versions[name]++
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "INSERT INTO public.operator_version"):
+		db.versions[args[0].(string)] = 1
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "FAIL"):
+		return nil, fmt.Errorf("syntax error")
+	}
+	db.statements = append(db.statements, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, fmt.Errorf("unexpected query %s", s.query)
+}
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+func TestApplyGoMigration(t *testing.T) {
+	name := "events-database-update-0.0.2.go"
+	migrate := func(statement string) *Migration {
+		return &Migration{Name: name, Version: "0.0.2", Go: &migrations.Migration{Version: "0.0.2", Run: func(tx *sql.Tx) error {
+			_, err := tx.Exec(statement)
+			return err
+		}}}
+	}
+
+	tests := []struct {
+		name               string
+		versions           map[string]int
+		statement          string
+		failed             bool
+		expectedVersions   map[string]int
+		expectedStatements int
+	}{
+		{"new migration", map[string]int{}, "UPDATE public.event", false, map[string]int{name: 1}, 1},
+		{"script of the same version applied", map[string]int{"0.0.2": 1}, "UPDATE public.event", false, map[string]int{"0.0.2": 1, name: 1}, 1},
+		{"already applied", map[string]int{name: 1}, "UPDATE public.event", false, map[string]int{name: 2}, 0},
+		{"failed migration", map[string]int{}, "FAIL", true, map[string]int{}, 0},
+	}
+
+	for _, test := range tests {
+		fakeDatabases[test.name] = &fakeDatabase{versions: test.versions}
+		db, err := sql.Open("fake", test.name)
+		if err != nil {
+			t.Fatalf("Unable to open the fake database: %v", err)
+		}
+
+		err = applyGoMigration(db, migrate(test.statement))
+		db.Close()
+		if (err != nil) != test.failed {
+			t.Errorf("applyGoMigration of %s returned %v", test.name, err)
+		}
+		result := fakeDatabases[test.name]
+		if fmt.Sprint(result.versions) != fmt.Sprint(test.expectedVersions) {
+			t.Errorf("applyGoMigration of %s left operator_version %v, expected %v", test.name, result.versions, test.expectedVersions)
+		}
+		if len(result.statements) != test.expectedStatements {
+			t.Errorf("applyGoMigration of %s ran %v, expected %d statements", test.name, result.statements, test.expectedStatements)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("database")
+
+// GetReadyEventsDatabasePod returns the first 'Events' database pod of instance found running and ready, nil if none
+func GetReadyEventsDatabasePod(c client.Client, instance *gramolav1alpha1.AppService) (*corev1.Pod, error) {
+	// List all pods of the Events Database
+	podList := &corev1.PodList{}
+	lbs := _deployment.GetAppServiceSelector(instance, _deployment.EventsDatabaseServiceName)
+	labelSelector := labels.SelectorFromSet(lbs)
+	listOps := &client.ListOptions{Namespace: instance.Namespace, LabelSelector: labelSelector}
+	if err := c.List(context.TODO(), podList, listOps); err != nil {
+		return nil, err
+	}
+
+	for i, pod := range podList.Items {
+		log.Info(fmt.Sprintf("pod: %s phase: %s statuses: %v", pod.Name, pod.Status.Phase, pod.Status.ContainerStatuses))
+		if pod.Status.Phase == corev1.PodRunning {
+			for _, containerStatus := range pod.Status.ContainerStatuses {
+				if containerStatus.Name == _deployment.EventsDatabaseServiceContainerName && containerStatus.Ready {
+					return &podList.Items[i], nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
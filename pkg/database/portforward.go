@@ -0,0 +1,64 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	errors "github.com/pkg/errors"
+)
+
+// ForwardPort forwards a random local port to port of the given pod through the API server, as commands are
+// executed, so the pod is reached wherever the operator runs. It returns the local port and a function to stop
+// forwarding
+func ForwardPort(pod *corev1.Pod, port int) (uint16, func(), error) {
+	restCfg, err := restClientConfig()
+	if err != nil {
+		return 0, nil, err
+	}
+	coreClient, err := corev1client.NewForConfig(restCfg)
+	if err != nil {
+		return 0, nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(restCfg)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	request := coreClient.RESTClient().
+		Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", request.URL())
+
+	stopChan, readyChan := make(chan struct{}), make(chan struct{})
+	errBuf := &bytes.Buffer{}
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf(":%d", port)}, stopChan, readyChan, &bytes.Buffer{}, errBuf)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- forwarder.ForwardPorts()
+	}()
+	select {
+	case err := <-failed:
+		return 0, nil, errors.Wrapf(err, "Failed forwarding port %d of %v/%v: %s", port, pod.Namespace, pod.Name, errBuf.String())
+	case <-readyChan:
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopChan)
+		return 0, nil, err
+	}
+	return ports[0].Local, func() { close(stopChan) }, nil
+}
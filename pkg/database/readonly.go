@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReadWritePsqlCommand runs psql against the events database in a session that can write even if the database is read-only
+const ReadWritePsqlCommand = "PGOPTIONS='-c default_transaction_read_only=off' " + PsqlCommand
+
+// ReadWriteOption is the run-time parameter of connections that can write even if the database is read-only
+const ReadWriteOption = "default_transaction_read_only=off"
+
+// terminateSessionsQuery ends the other sessions of the database user so that they reconnect with the new default
+const terminateSessionsQuery = "SELECT count(pg_terminate_backend(pid)) FROM pg_stat_activity " +
+	"WHERE datname = current_database() AND usename = current_user AND pid <> pg_backend_pid()"
+
+// IsReadOnly returns true if new sessions of the events database are read-only by default
+func IsReadOnly(pod *corev1.Pod) (bool, error) {
+	out, stderr, err := ExecuteRemoteCommand(pod, PsqlCommand+" -c \"SHOW default_transaction_read_only\"")
+	if err != nil {
+		return false, fmt.Errorf("Unable to check if the events database is read-only %v: %s", err, strings.TrimSpace(stderr))
+	}
+	return LastLine(out) == "on", nil
+}
+
+// SetReadOnly changes the default of the events database to read-only, or back to read-write, and ends the
+// other sessions of the database user so that the events service reconnects with the new default. The setting
+// is kept by the database, so it survives restarts of the operator and of the database
+func SetReadOnly(pod *corev1.Pod, readOnly bool) error {
+	value := "off"
+	if readOnly {
+		value = "on"
+	}
+	command := ReadWritePsqlCommand +
+		fmt.Sprintf(" -c \"ALTER DATABASE $POSTGRESQL_DATABASE SET default_transaction_read_only = %s\"", value) +
+		fmt.Sprintf(" -c \"%s\"", terminateSessionsQuery)
+	if _, stderr, err := ExecuteRemoteCommand(pod, command); err != nil {
+		return fmt.Errorf("Unable to set default_transaction_read_only to %s %v: %s", value, err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
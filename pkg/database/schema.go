@@ -0,0 +1,119 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// SchemaManifest declares the tables, columns, constraints and sequences a release expects
+type SchemaManifest struct {
+	Tables    []TableManifest `json:"tables"`
+	Sequences []string        `json:"sequences,omitempty"`
+}
+
+// TableManifest declares a table by its qualified name, its columns map to their information_schema data_type
+type TableManifest struct {
+	Name        string            `json:"name"`
+	Columns     map[string]string `json:"columns"`
+	Constraints []string          `json:"constraints,omitempty"`
+}
+
+// ParseSchemaManifest parses a YAML manifest
+func ParseSchemaManifest(data string) (*SchemaManifest, error) {
+	manifest := &SchemaManifest{}
+	if err := yaml.UnmarshalStrict([]byte(data), manifest); err != nil {
+		return nil, fmt.Errorf("Invalid schema manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// Schema is the actual schema of the database, objects are keyed by qualified name
+type Schema struct {
+	// Columns by table, mapped to their data type
+	Columns map[string]map[string]string
+	// Constraints by table
+	Constraints map[string]map[string]bool
+	// Sequences found
+	Sequences map[string]bool
+}
+
+// introspectSchemaQuery lists kind|table or sequence|name|data type of the user objects of the database
+const introspectSchemaQuery = "" +
+	"SELECT 'column', table_schema || '.' || table_name, column_name, data_type FROM information_schema.columns WHERE table_schema NOT IN ('pg_catalog', 'information_schema') " +
+	"UNION ALL SELECT 'constraint', table_schema || '.' || table_name, constraint_name, '' FROM information_schema.table_constraints WHERE table_schema NOT IN ('pg_catalog', 'information_schema') " +
+	"UNION ALL SELECT 'sequence', sequence_schema || '.' || sequence_name, '', '' FROM information_schema.sequences"
+
+// IntrospectSchema reads the schema of the events database from information_schema
+func IntrospectSchema(pod *corev1.Pod) (*Schema, error) {
+	out, stderr, err := StreamRemoteCommand(pod, psql([]string{introspectSchemaQuery}), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to introspect the schema %v: %s", err, stderr)
+	}
+
+	schema := &Schema{
+		Columns:     map[string]map[string]string{},
+		Constraints: map[string]map[string]bool{},
+		Sequences:   map[string]bool{},
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 {
+			continue
+		}
+		switch fields[0] {
+		case "column":
+			if schema.Columns[fields[1]] == nil {
+				schema.Columns[fields[1]] = map[string]string{}
+			}
+			schema.Columns[fields[1]][fields[2]] = fields[3]
+		case "constraint":
+			if schema.Constraints[fields[1]] == nil {
+				schema.Constraints[fields[1]] = map[string]bool{}
+			}
+			schema.Constraints[fields[1]][fields[2]] = true
+		case "sequence":
+			schema.Sequences[fields[1]] = true
+		}
+	}
+	return schema, nil
+}
+
+// Diff returns what the schema lacks or has different from the manifest, empty if it matches.
+// Objects not in the manifest are ignored
+func (s *Schema) Diff(manifest *SchemaManifest) []string {
+	diff := []string{}
+	for _, table := range manifest.Tables {
+		columns, ok := s.Columns[table.Name]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("missing table %s", table.Name))
+			continue
+		}
+		names := []string{}
+		for name := range table.Columns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if dataType, ok := columns[name]; !ok {
+				diff = append(diff, fmt.Sprintf("missing column %s.%s", table.Name, name))
+			} else if dataType != table.Columns[name] {
+				diff = append(diff, fmt.Sprintf("column %s.%s is %s instead of %s", table.Name, name, dataType, table.Columns[name]))
+			}
+		}
+		for _, constraint := range table.Constraints {
+			if !s.Constraints[table.Name][constraint] {
+				diff = append(diff, fmt.Sprintf("missing constraint %s on %s", constraint, table.Name))
+			}
+		}
+	}
+	for _, sequence := range manifest.Sequences {
+		if !s.Sequences[sequence] {
+			diff = append(diff, fmt.Sprintf("missing sequence %s", sequence))
+		}
+	}
+	return diff
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+)
+
+// EventsSchema is the schema holding the events table
+const EventsSchema = "public"
+
+// DatabaseContext describes the events database to migration scripts
+type DatabaseContext struct {
+	// Name of the database
+	Name string
+	// User owning the objects of the database
+	User string
+	// Password of the user
+	Password string
+	// Schema holding the events table
+	Schema string
+}
+
+// ScriptContext is the data migration scripts are rendered with as Go text/template, for instance
+// {{ .Database.User }}, {{ .AppService.Spec.Alias }} or {{ index .Secret "database-name" }}
+type ScriptContext struct {
+	// Database the scripts run against
+	Database DatabaseContext
+	// Values of the credentials Secret by key
+	Secret map[string]string
+	// AppService the scripts are run for
+	AppService *gramolav1alpha1.AppService
+	// Version of the operator
+	Version string
+}
+
+// NewScriptContext returns the context of the scripts of instance given the values of the credentials Secret
+func NewScriptContext(instance *gramolav1alpha1.AppService, secret map[string]string, operatorVersion string) *ScriptContext {
+	return &ScriptContext{
+		Database: DatabaseContext{
+			Name:     secret["database-name"],
+			User:     secret["database-user"],
+			Password: secret["database-password"],
+			Schema:   EventsSchema,
+		},
+		Secret:     secret,
+		AppService: instance,
+		Version:    operatorVersion,
+	}
+}
+
+// RenderScript renders the script of a migration, missing keys are errors
+func RenderScript(migration *Migration, context *ScriptContext) (string, error) {
+	funcs := template.FuncMap{
+		// Scripts written for the original {{DB_USERNAME}} replacement keep working
+		"DB_USERNAME": func() string { return context.Database.User },
+	}
+	tmpl, err := template.New(migration.Name).Funcs(funcs).Option("missingkey=error").Parse(migration.Script)
+	if err != nil {
+		return "", fmt.Errorf("Unable to parse script %s: %v", migration.Name, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, context); err != nil {
+		return "", fmt.Errorf("Unable to render script %s: %v", migration.Name, err)
+	}
+	return buf.String(), nil
+}
@@ -13,7 +13,7 @@ const (
 // GetEventsAnnotations returns a map with the annotations for Events
 func GetEventsAnnotations(cr *gramolav1alpha1.AppService) (labels map[string]string) {
 	annotations := map[string]string{
-		"app.openshift.io/connects-to": "events-database",
+		"app.openshift.io/connects-to": GetObjectName(cr, EventsDatabaseServiceName),
 		"app.openshift.io/vcs-ref":     ref,
 		"app.openshift.io/vcs-uri":     repo,
 	}
@@ -23,7 +23,7 @@ func GetEventsAnnotations(cr *gramolav1alpha1.AppService) (labels map[string]str
 // GetGatewayAnnotations returns a map with the annotations for Gateway
 func GetGatewayAnnotations(cr *gramolav1alpha1.AppService) (labels map[string]string) {
 	annotations := map[string]string{
-		"app.openshift.io/connects-to": "events",
+		"app.openshift.io/connects-to": GetObjectName(cr, EventsServiceName),
 		"app.openshift.io/vcs-ref":     ref,
 		"app.openshift.io/vcs-uri":     repo,
 	}
@@ -33,7 +33,7 @@ func GetGatewayAnnotations(cr *gramolav1alpha1.AppService) (labels map[string]st
 // GetFrontendAnnotations returns a map with the annotations for Gateway
 func GetFrontendAnnotations(cr *gramolav1alpha1.AppService) (labels map[string]string) {
 	annotations := map[string]string{
-		"app.openshift.io/connects-to": "gateway",
+		"app.openshift.io/connects-to": GetObjectName(cr, GatewayServiceName),
 		"app.openshift.io/vcs-ref":     ref,
 		"app.openshift.io/vcs-uri":     repo,
 	}
@@ -0,0 +1,159 @@
+package deployment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	version "github.com/redhat/gramola-operator/version"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Frontend branding names
+const (
+	FrontendBrandingConfigMapName = FrontendServiceName + "-branding"
+	FrontendBrandingVolumeName    = "branding"
+	FrontendBrandingMountPath     = "/opt/app-root/branding"
+	FrontendBrandingFileName      = "branding.json"
+	DefaultAlias                  = "Gramola"
+
+	frontendBrandingEnvName = "BRANDING_FILE"
+)
+
+// Branding is rendered as JSON into the branding ConfigMap read by the frontend
+type Branding struct {
+	Alias        string `json:"alias"`
+	Title        string `json:"title"`
+	LogoURL      string `json:"logoURL,omitempty"`
+	PrimaryColor string `json:"primaryColor"`
+	AccentColor  string `json:"accentColor"`
+}
+
+// Branding of each alias, overridden by spec.branding
+var aliasBrandings = map[string]Branding{
+	"Gramola": {
+		Alias:        "Gramola",
+		Title:        "Gramola",
+		PrimaryColor: "#cc0000",
+		AccentColor:  "#f0ab00",
+	},
+	"Gramophone": {
+		Alias:        "Gramophone",
+		Title:        "Gramophone",
+		PrimaryColor: "#004080",
+		AccentColor:  "#73bcf7",
+	},
+	"Phonograph": {
+		Alias:        "Phonograph",
+		Title:        "Phonograph",
+		PrimaryColor: "#3c3f42",
+		AccentColor:  "#c58c00",
+	},
+}
+
+// GetBranding returns the branding of the alias of the AppService (Gramola if none) with the overrides in spec.branding
+func GetBranding(instance *gramolav1alpha1.AppService) Branding {
+	branding, ok := aliasBrandings[instance.Spec.Alias]
+	if !ok {
+		branding = aliasBrandings[DefaultAlias]
+	}
+	if overrides := instance.Spec.Branding; overrides != nil {
+		if len(overrides.Title) > 0 {
+			branding.Title = overrides.Title
+		}
+		if len(overrides.LogoURL) > 0 {
+			branding.LogoURL = overrides.LogoURL
+		}
+		if len(overrides.PrimaryColor) > 0 {
+			branding.PrimaryColor = overrides.PrimaryColor
+		}
+		if len(overrides.AccentColor) > 0 {
+			branding.AccentColor = overrides.AccentColor
+		}
+	}
+	return branding
+}
+
+// getBrandingData returns the data of the branding ConfigMap
+func getBrandingData(instance *gramolav1alpha1.AppService) (map[string]string, error) {
+	content, err := json.MarshalIndent(GetBranding(instance), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{FrontendBrandingFileName: string(content)}, nil
+}
+
+// GetBrandingHash returns the hash of the branding, the frontend pods roll when it changes
+func GetBrandingHash(instance *gramolav1alpha1.AppService) (string, error) {
+	data, err := getBrandingData(instance)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(data[FrontendBrandingFileName]))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// NewFrontendBrandingConfigMap returns the ConfigMap with the branding of the frontend
+func NewFrontendBrandingConfigMap(instance *gramolav1alpha1.AppService, scheme *runtime.Scheme) (*corev1.ConfigMap, error) {
+	data, err := getBrandingData(instance)
+	if err != nil {
+		return nil, err
+	}
+	configMap := NewConfigMapFromData(instance, GetObjectName(instance, FrontendBrandingConfigMapName), instance.Namespace, data)
+
+	if err := controllerutil.SetControllerReference(instance, configMap, scheme); err != nil {
+		return nil, err
+	}
+
+	return configMap, nil
+}
+
+// NewFrontendBrandingConfigMapPatch returns a Patch
+func NewFrontendBrandingConfigMapPatch(instance *gramolav1alpha1.AppService, current *corev1.ConfigMap) (client.Patch, error) {
+	data, err := getBrandingData(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := client.MergeFrom(current.DeepCopy())
+
+	current.Labels["version"] = version.Version
+
+	current.Data = data
+
+	return patch, nil
+}
+
+func newFrontendBrandingEnv() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name:  frontendBrandingEnvName,
+		Value: path.Join(FrontendBrandingMountPath, FrontendBrandingFileName),
+	}
+}
+
+func newFrontendBrandingVolume(instance *gramolav1alpha1.AppService) corev1.Volume {
+	return corev1.Volume{
+		Name: FrontendBrandingVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: GetObjectName(instance, FrontendBrandingConfigMapName)},
+			},
+		},
+	}
+}
+
+func newFrontendBrandingVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      FrontendBrandingVolumeName,
+		MountPath: FrontendBrandingMountPath,
+		ReadOnly:  true,
+	}
+}
@@ -1,11 +1,13 @@
 package deployment
 
 import (
+	"fmt"
 	"os"
+	"path"
 	"strconv"
-	"strings"
 
 	routev1 "github.com/openshift/api/route/v1"
+	db "github.com/redhat/gramola-operator/db"
 	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
 	version "github.com/redhat/gramola-operator/version"
 
@@ -37,16 +39,23 @@ const (
 
 	EventsDatabasePersistanceVolumeName      = EventsDatabaseServiceName + "-data"
 	EventsDatabasePersistanceVolumeClaimName = EventsDatabaseServiceName
+	EventsDatabaseDefaultStorage             = "512Mi"
 )
 
 // Constants to locate the scripts to update the database
 const (
 	EventsDatabaseScriptsBaseEnvVarName = "DB_SCRIPTS_BASE_DIR"
-	EventsDatabaseUpdateScriptName      = "events-database-update-0.0.2.sql"
+	EventsDatabaseUpdateScriptVersion   = "0.0.2"
+	EventsDatabaseUpdateScriptName      = "events-database-update-" + EventsDatabaseUpdateScriptVersion + ".sql"
 	EventsDatabaseScriptsMountPath      = "/operator/scripts"
+	EventsDatabaseSchemaManifestName    = "events-database-schema.yaml"
 
 	EventsDatabaseCredentialsSecretName = EventsDatabaseServiceName
 	EventsDatabaseScriptsConfigMapName  = EventsDatabaseServiceName + "-scripts"
+
+	EventsDatabaseMigrationsVolumeName = EventsDatabaseServiceName + "-migrations"
+	EventsDatabaseMigrationsMountPath  = "/operator/migrations"
+	DefaultMigrationsImagePath         = "/migrations"
 )
 
 // EventsDatabaseServiceReplicas number of replicas for Events Service
@@ -55,6 +64,14 @@ var EventsDatabaseServiceReplicas = int32(1)
 // EventsServiceReplicas number of replicas for Events Service
 var EventsServiceReplicas = int32(2)
 
+// GetEventsDatabaseStorage returns the size of the events database volume
+func GetEventsDatabaseStorage(instance *gramolav1alpha1.AppService) string {
+	if instance.Spec.Database != nil && instance.Spec.Database.Storage != nil {
+		return instance.Spec.Database.Storage.String()
+	}
+	return EventsDatabaseDefaultStorage
+}
+
 // DatabaseCredentials contains the Database Credentials as a KV map
 var DatabaseCredentials = map[string]string{
 	"database-name":     "eventsdb",
@@ -62,20 +79,49 @@ var DatabaseCredentials = map[string]string{
 	"database-user":     "luke",
 }
 
-// DbScriptsBasePath point to the directory where the scripts to update the database should be
-var DbScriptsBasePath = os.Getenv(EventsDatabaseScriptsBaseEnvVarName) + "/db"
+// DbScriptsBasePath points to a directory overriding the scripts embedded in the operator, empty unless DB_SCRIPTS_BASE_DIR is set
+var DbScriptsBasePath = getDbScriptsBasePath()
 
-// getDatabaseScriptsMap returns a KV map with script names as Ks and Script File names as Vs
-func getDatabaseScriptsMap() map[string]string {
+func getDbScriptsBasePath() string {
+	if baseDir := os.Getenv(EventsDatabaseScriptsBaseEnvVarName); len(baseDir) > 0 {
+		return baseDir + "/db"
+	}
+	return ""
+}
+
+// readDatabaseScript returns a script from DbScriptsBasePath if set or from the scripts embedded in the operator
+func readDatabaseScript(name string) (string, error) {
+	if len(DbScriptsBasePath) > 0 {
+		data, err := util.ReadFile(DbScriptsBasePath, name)
+		if err != nil {
+			return "", fmt.Errorf("Unable to read database script %s from %s: %v", name, DbScriptsBasePath, err)
+		}
+		return data, nil
+	}
+
+	data, err := db.Scripts.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("Database script %s is not embedded in the operator: %v", name, err)
+	}
+	return string(data), nil
+}
+
+// GetDatabaseScriptsMap returns a KV map with script names as Ks and Script templates as Vs
+func GetDatabaseScriptsMap() (map[string]string, error) {
 	scripts := make(map[string]string)
-	databaseUser := DatabaseCredentials["database-user"]
 
-	if dbUpdateScriptData, err := util.ReadFile(DbScriptsBasePath, EventsDatabaseUpdateScriptName); err == nil {
-		dbUpdateScriptDataReplaced := strings.Replace(dbUpdateScriptData, "{{DB_USERNAME}}", databaseUser, -1)
-		scripts[EventsDatabaseUpdateScriptName] = dbUpdateScriptDataReplaced
+	dbUpdateScriptData, err := readDatabaseScript(EventsDatabaseUpdateScriptName)
+	if err != nil {
+		return nil, err
 	}
+	scripts[EventsDatabaseUpdateScriptName] = dbUpdateScriptData
 
-	return scripts
+	return scripts, nil
+}
+
+// GetEventsDatabaseSchemaManifest returns the manifest of the schema expected after Gramola's migrations
+func GetEventsDatabaseSchemaManifest() (string, error) {
+	return readDatabaseScript(EventsDatabaseSchemaManifestName)
 }
 
 // NewEventsDatabaseCredentialsSecret returns a Secret with the Events Database credentials
@@ -88,7 +134,7 @@ func NewEventsDatabaseCredentialsSecret(instance *gramolav1alpha1.AppService, sc
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      EventsDatabaseCredentialsSecretName,
+			Name:      GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
@@ -102,10 +148,9 @@ func NewEventsDatabaseCredentialsSecret(instance *gramolav1alpha1.AppService, sc
 	return secret, nil
 }
 
-// NewEventsDatabaseScriptsConfigMap returns a ConfigMap given a data object
+// NewEventsDatabaseScriptsConfigMap returns an empty ConfigMap, rendered scripts are added before they are run
 func NewEventsDatabaseScriptsConfigMap(instance *gramolav1alpha1.AppService, scheme *runtime.Scheme) (*corev1.ConfigMap, error) {
 	labels := GetAppServiceLabels(instance, EventsDatabaseServiceName)
-	scripts := getDatabaseScriptsMap()
 
 	configMap := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -113,11 +158,10 @@ func NewEventsDatabaseScriptsConfigMap(instance *gramolav1alpha1.AppService, sch
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      EventsDatabaseScriptsConfigMapName,
+			Name:      GetObjectName(instance, EventsDatabaseScriptsConfigMapName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
-		Data: scripts,
 	}
 
 	if err := controllerutil.SetControllerReference(instance, configMap, scheme); err != nil {
@@ -133,10 +177,16 @@ func NewEventsDatabaseScriptsConfigMapPatch(current *corev1.ConfigMap) client.Pa
 
 	current.Labels["version"] = version.Version
 
-	scripts := getDatabaseScriptsMap()
-	for k, v := range scripts {
-		current.Data[k] = v
-	}
+	return patch
+}
+
+// NewEventsDatabaseScriptsConfigMapDataPatch returns a Patch that leaves exactly the (rendered) scripts in the ConfigMap
+func NewEventsDatabaseScriptsConfigMapDataPatch(current *corev1.ConfigMap, scripts map[string]string) client.Patch {
+	patch := client.MergeFrom(current.DeepCopy())
+
+	current.Labels["version"] = version.Version
+
+	current.Data = scripts
 
 	return patch
 }
@@ -153,14 +203,76 @@ func NewEventsDatabaseCredentialsSecretPatch(current *corev1.Secret) client.Patc
 }
 
 // NewEventsDatabaseDeploymentPatch returns a Patch
-func NewEventsDatabaseDeploymentPatch(current *appsv1.Deployment) client.Patch {
+func NewEventsDatabaseDeploymentPatch(instance *gramolav1alpha1.AppService, current *appsv1.Deployment) client.Patch {
 	patch := client.MergeFrom(current.DeepCopy())
 
 	current.Labels["version"] = version.Version
 
+	podSpec := &current.Spec.Template.Spec
+	podSpec.InitContainers = newEventsDatabaseMigrationsInitContainers(instance)
+
+	volumeFound := false
+	for _, volume := range podSpec.Volumes {
+		volumeFound = volumeFound || volume.Name == EventsDatabaseMigrationsVolumeName
+	}
+	if !volumeFound {
+		podSpec.Volumes = append(podSpec.Volumes, newEventsDatabaseMigrationsVolume())
+	}
+	mountFound := false
+	for _, volumeMount := range podSpec.Containers[0].VolumeMounts {
+		mountFound = mountFound || volumeMount.Name == EventsDatabaseMigrationsVolumeName
+	}
+	if !mountFound {
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, newEventsDatabaseMigrationsVolumeMount())
+	}
+
 	return patch
 }
 
+// newEventsDatabaseMigrationsInitContainers returns an init container per image migration source that
+// copies its scripts to <EventsDatabaseMigrationsMountPath>/<source name>
+func newEventsDatabaseMigrationsInitContainers(instance *gramolav1alpha1.AppService) []corev1.Container {
+	containers := []corev1.Container{}
+	for _, source := range instance.Spec.MigrationSources {
+		if source.Image == nil {
+			continue
+		}
+		dir := path.Join(EventsDatabaseMigrationsMountPath, source.Name)
+		containers = append(containers, corev1.Container{
+			Name:            GetMigrationsInitContainerName(source.Name),
+			Image:           source.Image.Image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command: []string{
+				"sh", "-c",
+				fmt.Sprintf("mkdir -p %s && cp %s/*.sql %s/", dir, util.NVL(source.Image.Path, DefaultMigrationsImagePath), dir),
+			},
+			VolumeMounts: []corev1.VolumeMount{newEventsDatabaseMigrationsVolumeMount()},
+		})
+	}
+	return containers
+}
+
+// GetMigrationsInitContainerName returns the name of the init container that copies the scripts of an image migration source
+func GetMigrationsInitContainerName(source string) string {
+	return "migrations-" + source
+}
+
+func newEventsDatabaseMigrationsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: EventsDatabaseMigrationsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func newEventsDatabaseMigrationsVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      EventsDatabaseMigrationsVolumeName,
+		MountPath: EventsDatabaseMigrationsMountPath,
+	}
+}
+
 // NewEventsDeploymentPatch returns a Patch
 func NewEventsDeploymentPatch(current *appsv1.Deployment) client.Patch {
 	patch := client.MergeFrom(current.DeepCopy())
@@ -249,7 +361,7 @@ func NewEventsDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.S
 				SecretKeyRef: &corev1.SecretKeySelector{
 					Key: "database-user",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: EventsDatabaseCredentialsSecretName,
+						Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 					},
 				},
 			},
@@ -260,7 +372,7 @@ func NewEventsDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.S
 				SecretKeyRef: &corev1.SecretKeySelector{
 					Key: "database-password",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: EventsDatabaseCredentialsSecretName,
+						Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 					},
 				},
 			},
@@ -271,14 +383,14 @@ func NewEventsDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.S
 				SecretKeyRef: &corev1.SecretKeySelector{
 					Key: "database-name",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: EventsDatabaseCredentialsSecretName,
+						Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 					},
 				},
 			},
 		},
 		{
 			Name:  "DB_SERVICE_NAME",
-			Value: EventsDatabaseServiceName,
+			Value: GetObjectName(instance, EventsDatabaseServiceName),
 		},
 		{
 			Name:  "DB_SERVICE_PORT",
@@ -292,7 +404,7 @@ func NewEventsDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.S
 			APIVersion: "apps/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        EventsServiceName,
+			Name:        GetObjectName(instance, EventsServiceName),
 			Namespace:   instance.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
@@ -387,7 +499,7 @@ func NewEventsDatabaseDeployment(instance *gramolav1alpha1.AppService, scheme *r
 				SecretKeyRef: &corev1.SecretKeySelector{
 					Key: "database-user",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: EventsDatabaseCredentialsSecretName,
+						Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 					},
 				},
 			},
@@ -398,7 +510,7 @@ func NewEventsDatabaseDeployment(instance *gramolav1alpha1.AppService, scheme *r
 				SecretKeyRef: &corev1.SecretKeySelector{
 					Key: "database-password",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: EventsDatabaseCredentialsSecretName,
+						Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 					},
 				},
 			},
@@ -409,7 +521,7 @@ func NewEventsDatabaseDeployment(instance *gramolav1alpha1.AppService, scheme *r
 				SecretKeyRef: &corev1.SecretKeySelector{
 					Key: "database-name",
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: EventsDatabaseCredentialsSecretName,
+						Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
 					},
 				},
 			},
@@ -422,7 +534,7 @@ func NewEventsDatabaseDeployment(instance *gramolav1alpha1.AppService, scheme *r
 			APIVersion: "apps/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      EventsDatabaseServiceName,
+			Name:      GetObjectName(instance, EventsDatabaseServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
@@ -491,16 +603,18 @@ func NewEventsDatabaseDeployment(instance *gramolav1alpha1.AppService, scheme *r
 									Name:      EventsDatabaseScriptsConfigMapName,
 									MountPath: EventsDatabaseScriptsMountPath,
 								},
+								newEventsDatabaseMigrationsVolumeMount(),
 							},
 							Env: env,
 						},
 					},
+					InitContainers: newEventsDatabaseMigrationsInitContainers(instance),
 					Volumes: []corev1.Volume{
 						{
 							Name: EventsDatabasePersistanceVolumeName,
 							VolumeSource: corev1.VolumeSource{
 								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-									ClaimName: EventsDatabasePersistanceVolumeClaimName,
+									ClaimName: GetObjectName(instance, EventsDatabasePersistanceVolumeClaimName),
 								},
 							},
 						},
@@ -509,11 +623,12 @@ func NewEventsDatabaseDeployment(instance *gramolav1alpha1.AppService, scheme *r
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
-										Name: EventsDatabaseScriptsConfigMapName,
+										Name: GetObjectName(instance, EventsDatabaseScriptsConfigMapName),
 									},
 								},
 							},
 						},
+						newEventsDatabaseMigrationsVolume(),
 					},
 				},
 			},
@@ -537,7 +652,7 @@ func NewEventsService(instance *gramolav1alpha1.AppService, scheme *runtime.Sche
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      EventsServiceName,
+			Name:      GetObjectName(instance, EventsServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
@@ -570,7 +685,7 @@ func NewEventsDatabaseService(instance *gramolav1alpha1.AppService, scheme *runt
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      EventsDatabaseServiceName,
+			Name:      GetObjectName(instance, EventsDatabaseServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
@@ -606,14 +721,14 @@ func NewEventsRoute(instance *gramolav1alpha1.AppService, scheme *runtime.Scheme
 			APIVersion: routev1.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      EventsServiceName,
+			Name:      GetObjectName(instance, EventsServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
 		Spec: routev1.RouteSpec{
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
-				Name: EventsServiceName,
+				Name: GetObjectName(instance, EventsServiceName),
 			},
 			Port: &routev1.RoutePort{
 				TargetPort: targetPort,
@@ -1,6 +1,8 @@
 package deployment
 
 import (
+	"strconv"
+
 	routev1 "github.com/openshift/api/route/v1"
 	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
 	version "github.com/redhat/gramola-operator/version"
@@ -29,8 +31,13 @@ const (
 // FrontendServiceReplicas number of replicas for Frontend Service
 var FrontendServiceReplicas = int32(2)
 
-// NewFrontendDeploymentPatch returns a Patch
-func NewFrontendDeploymentPatch(current *appsv1.Deployment) client.Patch {
+// NewFrontendDeploymentPatch returns a Patch, the branding is mounted if it wasn't and the pods roll when its hash changes
+func NewFrontendDeploymentPatch(instance *gramolav1alpha1.AppService, current *appsv1.Deployment) (client.Patch, error) {
+	brandingHash, err := GetBrandingHash(instance)
+	if err != nil {
+		return nil, err
+	}
+
 	patch := client.MergeFrom(current.DeepCopy())
 
 	current.Labels["version"] = version.Version
@@ -74,7 +81,35 @@ func NewFrontendDeploymentPatch(current *appsv1.Deployment) client.Patch {
 		TimeoutSeconds:      1,
 	}
 
-	return patch
+	if current.Spec.Template.Annotations == nil {
+		current.Spec.Template.Annotations = map[string]string{}
+	}
+	current.Spec.Template.Annotations[gramolav1alpha1.ConfigHashAnnotation] = brandingHash
+
+	podSpec := &current.Spec.Template.Spec
+	volumeFound := false
+	for _, volume := range podSpec.Volumes {
+		volumeFound = volumeFound || volume.Name == FrontendBrandingVolumeName
+	}
+	if !volumeFound {
+		podSpec.Volumes = append(podSpec.Volumes, newFrontendBrandingVolume(instance))
+	}
+	mountFound := false
+	for _, volumeMount := range podSpec.Containers[0].VolumeMounts {
+		mountFound = mountFound || volumeMount.Name == FrontendBrandingVolumeName
+	}
+	if !mountFound {
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, newFrontendBrandingVolumeMount())
+	}
+	envFound := false
+	for _, env := range podSpec.Containers[0].Env {
+		envFound = envFound || env.Name == frontendBrandingEnvName
+	}
+	if !envFound {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, newFrontendBrandingEnv())
+	}
+
+	return patch, nil
 }
 
 // NewFrontendServicePatch returns a Patch
@@ -101,11 +136,25 @@ func NewFrontendDeployment(instance *gramolav1alpha1.AppService, scheme *runtime
 	labels := GetAppServiceLabels(instance, FrontendServiceName)
 	labels["app.kubernetes.io/name"] = "nodejs"
 
+	brandingHash, err := GetBrandingHash(instance)
+	if err != nil {
+		return nil, err
+	}
+
 	env := []corev1.EnvVar{
 		{
 			Name:  "NODE_ENV",
 			Value: "production",
 		},
+		{
+			Name:  "GATEWAY_SERVICE_NAME",
+			Value: GetObjectName(instance, GatewayServiceName),
+		},
+		{
+			Name:  "GATEWAY_SERVICE_PORT",
+			Value: strconv.Itoa(GatewayServicePort),
+		},
+		newFrontendBrandingEnv(),
 	}
 
 	deployment := &appsv1.Deployment{
@@ -114,7 +163,7 @@ func NewFrontendDeployment(instance *gramolav1alpha1.AppService, scheme *runtime
 			APIVersion: "apps/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        FrontendServiceName,
+			Name:        GetObjectName(instance, FrontendServiceName),
 			Namespace:   instance.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
@@ -127,8 +176,12 @@ func NewFrontendDeployment(instance *gramolav1alpha1.AppService, scheme *runtime
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
+					Annotations: map[string]string{
+						gramolav1alpha1.ConfigHashAnnotation: brandingHash,
+					},
 				},
 				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{newFrontendBrandingVolume(instance)},
 					Containers: []corev1.Container{
 						{
 							Name:            FrontendServiceName,
@@ -183,7 +236,8 @@ func NewFrontendDeployment(instance *gramolav1alpha1.AppService, scheme *runtime
 								SuccessThreshold:    1,
 								TimeoutSeconds:      1,
 							},
-							Env: env,
+							Env:          env,
+							VolumeMounts: []corev1.VolumeMount{newFrontendBrandingVolumeMount()},
 						},
 					},
 				},
@@ -208,7 +262,7 @@ func NewFrontendService(instance *gramolav1alpha1.AppService, scheme *runtime.Sc
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      FrontendServiceName,
+			Name:      GetObjectName(instance, FrontendServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
@@ -244,14 +298,14 @@ func NewFrontendRoute(instance *gramolav1alpha1.AppService, scheme *runtime.Sche
 			APIVersion: routev1.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      FrontendServiceName,
+			Name:      GetObjectName(instance, FrontendServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
 		Spec: routev1.RouteSpec{
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
-				Name: FrontendServiceName,
+				Name: GetObjectName(instance, FrontendServiceName),
 			},
 			Port: &routev1.RoutePort{
 				TargetPort: targetPort,
@@ -1,6 +1,8 @@
 package deployment
 
 import (
+	"strconv"
+
 	routev1 "github.com/openshift/api/route/v1"
 	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
 	version "github.com/redhat/gramola-operator/version"
@@ -106,6 +108,14 @@ func NewGatewayDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.
 			Name:  "NODE_ENV",
 			Value: "production",
 		},
+		{
+			Name:  "EVENTS_SERVICE_NAME",
+			Value: GetObjectName(instance, EventsServiceName),
+		},
+		{
+			Name:  "EVENTS_SERVICE_PORT",
+			Value: strconv.Itoa(EventsServicePort),
+		},
 	}
 
 	deployment := &appsv1.Deployment{
@@ -114,7 +124,7 @@ func NewGatewayDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.
 			APIVersion: "apps/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        GatewayServiceName,
+			Name:        GetObjectName(instance, GatewayServiceName),
 			Namespace:   instance.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
@@ -208,7 +218,7 @@ func NewGatewayService(instance *gramolav1alpha1.AppService, scheme *runtime.Sch
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      GatewayServiceName,
+			Name:      GetObjectName(instance, GatewayServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
@@ -244,14 +254,14 @@ func NewGatewayRoute(instance *gramolav1alpha1.AppService, scheme *runtime.Schem
 			APIVersion: routev1.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      GatewayServiceName,
+			Name:      GetObjectName(instance, GatewayServiceName),
 			Namespace: instance.Namespace,
 			Labels:    labels,
 		},
 		Spec: routev1.RouteSpec{
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
-				Name: GatewayServiceName,
+				Name: GetObjectName(instance, GatewayServiceName),
 			},
 			Port: &routev1.RoutePort{
 				TargetPort: targetPort,
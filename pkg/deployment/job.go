@@ -0,0 +1,134 @@
+package deployment
+
+import (
+	"strconv"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Events Database Jobs
+const (
+	EventsDatabaseJobComponentName  = EventsDatabaseServiceName + "-job"
+	EventsDatabaseJobDataVolumeName = "data"
+	EventsDatabaseJobDataMountPath  = "/data"
+)
+
+// EventsDatabaseJobBackoffLimit number of retries of an Events Database Job
+var EventsDatabaseJobBackoffLimit = int32(2)
+
+// GetEventsDatabaseClientEnv returns the environment a psql client needs to connect to the Events Database of instance
+func GetEventsDatabaseClientEnv(instance *gramolav1alpha1.AppService) []corev1.EnvVar {
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				Key: key,
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: GetObjectName(instance, EventsDatabaseCredentialsSecretName),
+				},
+			},
+		}
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:      "POSTGRESQL_USER",
+			ValueFrom: secretKeyRef("database-user"),
+		},
+		{
+			Name:      "POSTGRESQL_DATABASE",
+			ValueFrom: secretKeyRef("database-name"),
+		},
+		{
+			Name:      "PGPASSWORD",
+			ValueFrom: secretKeyRef("database-password"),
+		},
+		{
+			Name:  "PGHOST",
+			Value: GetObjectName(instance, EventsDatabaseServiceName),
+		},
+		{
+			Name:  "PGPORT",
+			Value: strconv.Itoa(EventsDatabaseServicePort),
+		},
+	}
+}
+
+// NewEventsDatabaseJob returns a Job owned by owner that runs a shell command against the Events Database of instance. If
+// claimName is not empty the PersistentVolumeClaim is mounted on EventsDatabaseJobDataMountPath. The last line printed
+// by the command is kept as the termination message of the pod
+func NewEventsDatabaseJob(instance *gramolav1alpha1.AppService, owner metav1.Object, name string, command string, claimName string, scheme *runtime.Scheme) (*batchv1.Job, error) {
+	labels := GetAppServiceLabels(instance, EventsDatabaseJobComponentName)
+	labels["app.kubernetes.io/name"] = "postgresql"
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &EventsDatabaseJobBackoffLimit,
+			Template:     newEventsDatabaseJobPodTemplate(instance, labels, command, claimName),
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(owner, job, scheme); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// newEventsDatabaseJobPodTemplate returns the pod template of the Jobs running commands against the Events Database
+func newEventsDatabaseJobPodTemplate(instance *gramolav1alpha1.AppService, labels map[string]string, command string, claimName string) corev1.PodTemplateSpec {
+	container := corev1.Container{
+		Name:            EventsDatabaseJobComponentName,
+		Image:           EventsDatabaseServiceImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command: []string{
+			"/bin/bash",
+			"-c",
+			"set -o pipefail; " + command + " | tail -n 1 | tee " + corev1.TerminationMessagePathDefault,
+		},
+		Env:                      GetEventsDatabaseClientEnv(instance),
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	volumes := []corev1.Volume{}
+	if len(claimName) > 0 {
+		container.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      EventsDatabaseJobDataVolumeName,
+				MountPath: EventsDatabaseJobDataMountPath,
+			},
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: EventsDatabaseJobDataVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+				},
+			},
+		})
+	}
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers:    []corev1.Container{container},
+			Volumes:       volumes,
+		},
+	}
+}
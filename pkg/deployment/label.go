@@ -11,12 +11,23 @@ const (
 
 // GetAppServiceLabels returns a map with the labels we want for all AppService assets
 func GetAppServiceLabels(cr *gramolav1alpha1.AppService, component string) (labels map[string]string) {
-	labels = map[string]string{
-		"app":                         AppName,
-		"component":                   component,
-		"app.kubernetes.io/component": component,
-		"app.kubernetes.io/instance":  component,
-		"app.kubernetes.io/part-of":   AppName + "-app",
-	}
+	labels = GetAppServiceSelector(cr, component)
+	labels["app"] = AppName
+	labels["app.kubernetes.io/component"] = component
+	labels["app.kubernetes.io/part-of"] = AppName + "-app"
 	return labels
 }
+
+// GetAppServiceSelector returns the labels that tell the pods (or jobs) of a component of an AppService from those of
+// the other AppServices in the namespace. AppServices with legacy names keep the instance label they had, the component,
+// as Deployment selectors can't change
+func GetAppServiceSelector(cr *gramolav1alpha1.AppService, component string) map[string]string {
+	instance := cr.Name
+	if HasLegacyNames(cr) {
+		instance = component
+	}
+	return map[string]string{
+		"component":                  component,
+		"app.kubernetes.io/instance": instance,
+	}
+}
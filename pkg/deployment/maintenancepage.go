@@ -0,0 +1,193 @@
+package deployment
+
+import (
+	routev1 "github.com/openshift/api/route/v1"
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Maintenance page names, the page is served with the frontend port so the Route target port stays the same
+const (
+	MaintenancePageName      = "maintenance-page"
+	MaintenancePagePort      = FrontendServicePort
+	MaintenancePagePortName  = FrontendServicePortName
+	MaintenancePageImage     = "registry.access.redhat.com/ubi8/nginx-118"
+	MaintenancePageMountPath = "/opt/app-root/src"
+	MaintenancePageFileName  = "index.html"
+)
+
+// MaintenancePageContent is the page shown while the frontend is replaced
+const MaintenancePageContent = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta http-equiv="refresh" content="30">
+  <title>Gramola - Under maintenance</title>
+</head>
+<body>
+  <h1>Gramola is under maintenance</h1>
+  <p>We are updating our events, please come back in a few minutes.</p>
+</body>
+</html>
+`
+
+// NewFrontendRouteTargetPatch returns a Patch that points the frontend Route to a Service
+func NewFrontendRouteTargetPatch(current *routev1.Route, serviceName string) client.Patch {
+	patch := client.MergeFrom(current.DeepCopy())
+
+	current.Spec.To.Name = serviceName
+
+	return patch
+}
+
+// NewMaintenancePageConfigMap returns the ConfigMap with the maintenance page
+func NewMaintenancePageConfigMap(instance *gramolav1alpha1.AppService, scheme *runtime.Scheme) (*corev1.ConfigMap, error) {
+	configMap := NewConfigMapFromData(instance, GetObjectName(instance, MaintenancePageName), instance.Namespace, map[string]string{
+		MaintenancePageFileName: MaintenancePageContent,
+	})
+
+	if err := controllerutil.SetControllerReference(instance, configMap, scheme); err != nil {
+		return nil, err
+	}
+
+	return configMap, nil
+}
+
+// NewMaintenancePageDeployment returns the deployment object for the maintenance page
+func NewMaintenancePageDeployment(instance *gramolav1alpha1.AppService, scheme *runtime.Scheme) (*appsv1.Deployment, error) {
+	labels := GetAppServiceLabels(instance, MaintenancePageName)
+	labels["app.kubernetes.io/name"] = "nginx"
+
+	replicas := int32(1)
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetObjectName(instance, MaintenancePageName),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            MaintenancePageName,
+							Image:           MaintenancePageImage,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         []string{"nginx", "-g", "daemon off;"},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          MaintenancePagePortName,
+									ContainerPort: MaintenancePagePort,
+									Protocol:      "TCP",
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("16Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("64Mi"),
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/",
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: MaintenancePagePort,
+										},
+										Scheme: corev1.URISchemeHTTP,
+									},
+								},
+								PeriodSeconds:    2,
+								SuccessThreshold: 1,
+								TimeoutSeconds:   1,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      MaintenancePageName,
+									MountPath: MaintenancePageMountPath,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: MaintenancePageName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: GetObjectName(instance, MaintenancePageName),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(instance, deployment, scheme); err != nil {
+		return nil, err
+	}
+
+	return deployment, nil
+}
+
+// NewMaintenancePageService return the Service of the maintenance page
+func NewMaintenancePageService(instance *gramolav1alpha1.AppService, scheme *runtime.Scheme) (*corev1.Service, error) {
+	labels := GetAppServiceLabels(instance, MaintenancePageName)
+	labels["app.kubernetes.io/name"] = "nginx"
+
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetObjectName(instance, MaintenancePageName),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:     MaintenancePagePortName,
+					Port:     MaintenancePagePort,
+					Protocol: "TCP",
+				},
+			},
+			Selector: labels,
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(instance, service, scheme); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
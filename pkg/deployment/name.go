@@ -0,0 +1,20 @@
+package deployment
+
+import (
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+)
+
+// HasLegacyNames checks if the objects of an AppService keep the unprefixed names of the AppServices reconciled before
+// several of them could live in the same namespace
+func HasLegacyNames(instance *gramolav1alpha1.AppService) bool {
+	return instance.Annotations[gramolav1alpha1.LegacyNamesAnnotation] == "true"
+}
+
+// GetObjectName returns the name of an object of an AppService given its unprefixed name, <AppService name>-<name>,
+// or the unprefixed name if the AppService has legacy names
+func GetObjectName(instance *gramolav1alpha1.AppService, name string) string {
+	if HasLegacyNames(instance) {
+		return name
+	}
+	return instance.Name + "-" + name
+}
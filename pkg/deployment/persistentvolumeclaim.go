@@ -5,6 +5,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // NewPersistentVolumeClaim returns a PersistenceVolumeClaim given name, namespace, size, etc.
@@ -36,3 +37,15 @@ func NewPersistentVolumeClaim(cr *gramolav1alpha1.AppService, name string, names
 	}
 
 }
+
+// NewPersistentVolumeClaimResizePatch returns a Patch that requests pvcClaimSize for the claim
+func NewPersistentVolumeClaimResizePatch(current *corev1.PersistentVolumeClaim, pvcClaimSize string) client.Patch {
+	patch := client.MergeFrom(current.DeepCopy())
+
+	if current.Spec.Resources.Requests == nil {
+		current.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	current.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(pvcClaimSize)
+
+	return patch
+}
@@ -0,0 +1,76 @@
+package deployment
+
+import (
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	version "github.com/redhat/gramola-operator/version"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	util "github.com/redhat/gramola-operator/pkg/util"
+)
+
+// Events Database retention
+const (
+	EventsDatabaseRetentionCronJobName = EventsDatabaseServiceName + "-retention"
+	EventsDatabaseRetentionSchedule    = "0 3 * * *"
+)
+
+// EventsDatabaseRetentionJobsHistoryLimit number of finished purge Jobs kept
+var EventsDatabaseRetentionJobsHistoryLimit = int32(3)
+
+// NewEventsDatabaseRetentionCronJob returns a CronJob that runs command, the purge of past events, on the retention schedule
+func NewEventsDatabaseRetentionCronJob(instance *gramolav1alpha1.AppService, command string, scheme *runtime.Scheme) (*batchv1beta1.CronJob, error) {
+	labels := GetAppServiceLabels(instance, EventsDatabaseRetentionCronJobName)
+	labels["app.kubernetes.io/name"] = "postgresql"
+
+	cronJob := &batchv1beta1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CronJob",
+			APIVersion: "batch/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetObjectName(instance, EventsDatabaseRetentionCronJobName),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   util.NVL(instance.Spec.Retention.Schedule, EventsDatabaseRetentionSchedule),
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &EventsDatabaseRetentionJobsHistoryLimit,
+			FailedJobsHistoryLimit:     &EventsDatabaseRetentionJobsHistoryLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &EventsDatabaseJobBackoffLimit,
+					Template:     newEventsDatabaseJobPodTemplate(instance, labels, command, ""),
+				},
+			},
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(instance, cronJob, scheme); err != nil {
+		return nil, err
+	}
+
+	return cronJob, nil
+}
+
+// NewEventsDatabaseRetentionCronJobPatch returns a Patch
+func NewEventsDatabaseRetentionCronJobPatch(current *batchv1beta1.CronJob, desired *batchv1beta1.CronJob) client.Patch {
+	patch := client.MergeFrom(current.DeepCopy())
+
+	current.Labels["version"] = version.Version
+
+	current.Spec.Schedule = desired.Spec.Schedule
+	current.Spec.Suspend = desired.Spec.Suspend
+	current.Spec.JobTemplate = desired.Spec.JobTemplate
+
+	return patch
+}
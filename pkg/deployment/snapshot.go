@@ -0,0 +1,44 @@
+package deployment
+
+import (
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FinalSnapshotSuffix is appended to the name of the AppService to name its final snapshot
+const FinalSnapshotSuffix = "-final-snapshot"
+
+// NewFinalSnapshotDataExport returns the AppServiceDataExport taken before an AppService with the Snapshot deletion policy
+// is deleted. It's not owned by the AppService so that it outlives it, and so does the exported file
+func NewFinalSnapshotDataExport(instance *gramolav1alpha1.AppService) *gramolav1alpha1.AppServiceDataExport {
+	labels := GetAppServiceLabels(instance, EventsDatabaseServiceName)
+	name := instance.Name + FinalSnapshotSuffix
+
+	spec := gramolav1alpha1.AppServiceDataExportSpec{
+		AppService: instance.Name,
+		Format:     gramolav1alpha1.DataFormatJSONLines,
+		Storage: gramolav1alpha1.DataStorage{
+			ConfigMap: &gramolav1alpha1.DataConfigMapStorage{Name: name},
+		},
+	}
+	if snapshot := instance.Spec.DeletionSnapshot; snapshot != nil {
+		spec.Storage = snapshot.Storage
+		if len(snapshot.Format) > 0 {
+			spec.Format = snapshot.Format
+		}
+	}
+
+	return &gramolav1alpha1.AppServiceDataExport{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "AppServiceDataExport",
+			APIVersion: gramolav1alpha1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+}
@@ -0,0 +1,148 @@
+package feed
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	"github.com/redhat/gramola-operator/pkg/gateway"
+
+	errors "github.com/pkg/errors"
+)
+
+// Record is a feed entry flattened as field name/value pairs
+type Record map[string]string
+
+// Gramola event fields that can be mapped
+const (
+	FieldName        = "name"
+	FieldArtist      = "artist"
+	FieldDescription = "description"
+	FieldAddress     = "address"
+	FieldCity        = "city"
+	FieldProvince    = "province"
+	FieldCountry     = "country"
+	FieldLocation    = "location"
+	FieldImage       = "image"
+	FieldStartDate   = "startDate"
+	FieldEndDate     = "endDate"
+	FieldStartTime   = "startTime"
+	FieldEndTime     = "endTime"
+)
+
+// DefaultICalFieldMapping maps Gramola event fields onto standard VEVENT properties
+var DefaultICalFieldMapping = map[string]string{
+	FieldName:        "SUMMARY",
+	FieldArtist:      "ORGANIZER.CN",
+	FieldDescription: "DESCRIPTION",
+	FieldLocation:    "LOCATION",
+	FieldImage:       "ATTACH",
+	FieldStartDate:   "DTSTART.date",
+	FieldEndDate:     "DTEND.date",
+	FieldStartTime:   "DTSTART.time",
+	FieldEndTime:     "DTEND.time",
+}
+
+// DefaultJSONFieldMapping maps Gramola event fields onto fields with the same name
+var DefaultJSONFieldMapping = map[string]string{
+	FieldName:        FieldName,
+	FieldArtist:      FieldArtist,
+	FieldDescription: FieldDescription,
+	FieldAddress:     FieldAddress,
+	FieldCity:        FieldCity,
+	FieldProvince:    FieldProvince,
+	FieldCountry:     FieldCountry,
+	FieldLocation:    FieldLocation,
+	FieldImage:       FieldImage,
+	FieldStartDate:   FieldStartDate,
+	FieldEndDate:     FieldEndDate,
+	FieldStartTime:   FieldStartTime,
+	FieldEndTime:     FieldEndTime,
+}
+
+// Fetch downloads the feed from url
+func Fetch(httpClient *http.Client, url string) ([]byte, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed fetching feed %s", url)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, fmt.Errorf("Failed fetching feed %s: %s", url, response.Status)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+// Parse returns the records found in data given the format of the feed
+func Parse(format gramolav1alpha1.EventFeedFormat, data []byte, itemsPath string) ([]Record, error) {
+	switch format {
+	case gramolav1alpha1.EventFeedFormatICal:
+		return ParseICal(data)
+	case gramolav1alpha1.EventFeedFormatJSON:
+		return ParseJSON(data, itemsPath)
+	}
+	return nil, fmt.Errorf("Unsupported feed format %s", format)
+}
+
+// FieldMapping returns the default mapping for format overridden by the user supplied one
+func FieldMapping(format gramolav1alpha1.EventFeedFormat, overrides map[string]string) map[string]string {
+	mapping := make(map[string]string)
+	defaults := DefaultJSONFieldMapping
+	if format == gramolav1alpha1.EventFeedFormatICal {
+		defaults = DefaultICalFieldMapping
+	}
+	for k, v := range defaults {
+		mapping[k] = v
+	}
+	for k, v := range overrides {
+		mapping[k] = v
+	}
+	return mapping
+}
+
+// MapRecord maps a record onto a Gramola event, a name and a start date are mandatory
+func MapRecord(record Record, mapping map[string]string) (*gateway.Event, error) {
+	field := func(name string) string {
+		if source, ok := mapping[name]; ok && len(source) > 0 {
+			return strings.TrimSpace(record[source])
+		}
+		return ""
+	}
+
+	event := &gateway.Event{
+		Name:        field(FieldName),
+		Artist:      field(FieldArtist),
+		Description: field(FieldDescription),
+		Address:     field(FieldAddress),
+		City:        field(FieldCity),
+		Province:    field(FieldProvince),
+		Country:     field(FieldCountry),
+		Location:    field(FieldLocation),
+		Image:       field(FieldImage),
+		StartDate:   field(FieldStartDate),
+		EndDate:     field(FieldEndDate),
+		StartTime:   field(FieldStartTime),
+		EndTime:     field(FieldEndTime),
+	}
+
+	if len(event.Name) == 0 {
+		return nil, fmt.Errorf("Entry has no value for %s (mapped from %s)", FieldName, mapping[FieldName])
+	}
+	if len(event.StartDate) == 0 {
+		return nil, fmt.Errorf("Entry %s has no value for %s (mapped from %s)", event.Name, FieldStartDate, mapping[FieldStartDate])
+	}
+	if len(event.EndDate) == 0 {
+		event.EndDate = event.StartDate
+	}
+	// Legacy column kept in sync with startDate
+	event.Date = event.StartDate
+
+	return event, nil
+}
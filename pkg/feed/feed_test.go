@@ -0,0 +1,80 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/events.ics":
+			w.Write([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	data, err := Fetch(server.Client(), server.URL+"/events.ics")
+	if err != nil {
+		t.Fatalf("Fetch returned an error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "BEGIN:VCALENDAR") {
+		t.Errorf("Fetch returned %q", data)
+	}
+
+	if _, err := Fetch(server.Client(), server.URL+"/missing.ics"); err == nil {
+		t.Errorf("Fetch of a missing feed didn't return an error")
+	} else if !strings.Contains(err.Error(), "404") {
+		t.Errorf("Fetch error %q doesn't carry the status", err)
+	}
+}
+
+func TestFetchUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if _, err := Fetch(nil, url); err == nil {
+		t.Errorf("Fetch of an unreachable feed didn't return an error")
+	}
+}
+
+func TestMapRecord(t *testing.T) {
+	mapping := FieldMapping(gramolav1alpha1.EventFeedFormatJSON, map[string]string{FieldName: "title", FieldCity: "venue.city"})
+
+	event, err := MapRecord(Record{
+		"title":      " Concert ",
+		"artist":     "Band",
+		"venue.city": "Madrid",
+		"startDate":  "2020-05-01",
+		"startTime":  "21:00",
+	}, mapping)
+	if err != nil {
+		t.Fatalf("MapRecord returned an error: %v", err)
+	}
+	if event.Name != "Concert" || event.Artist != "Band" || event.City != "Madrid" {
+		t.Errorf("MapRecord mapped %+v", event)
+	}
+	if event.EndDate != "2020-05-01" || event.Date != "2020-05-01" {
+		t.Errorf("MapRecord didn't default endDate and date to startDate: %+v", event)
+	}
+
+	tests := []struct {
+		name   string
+		record Record
+	}{
+		{"no name", Record{"startDate": "2020-05-01"}},
+		{"no start date", Record{"title": "Concert"}},
+	}
+	for _, test := range tests {
+		if _, err := MapRecord(test.record, mapping); err == nil {
+			t.Errorf("MapRecord of a record with %s didn't return an error", test.name)
+		}
+	}
+}
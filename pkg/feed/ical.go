@@ -0,0 +1,135 @@
+package feed
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// iCal date and date-time layouts as in RFC 5545
+const (
+	icalDateLayout        = "20060102"
+	icalDateTimeLayout    = "20060102T150405"
+	icalDateTimeUTCLayout = "20060102T150405Z"
+)
+
+// Layouts of the dates and times Gramola stores
+const (
+	EventDateLayout = "2006-01-02"
+	EventTimeLayout = "15:04"
+)
+
+// ParseICal returns a Record per VEVENT found in data. Properties are keyed by name, parameters
+// as NAME.PARAM and DTSTART/DTEND are also split into DTSTART.date and DTSTART.time
+func ParseICal(data []byte) ([]Record, error) {
+	records := []Record{}
+	var current Record
+
+	for i, line := range unfoldICal(data) {
+		name, params, value, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", i+1, err)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = Record{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current != nil {
+				records = append(records, current)
+			}
+			current = nil
+		case current != nil:
+			// Only the first occurrence of a property is kept
+			if _, ok := current[name]; ok {
+				continue
+			}
+			current[name] = value
+			for k, v := range params {
+				current[name+"."+k] = v
+			}
+			if name == "DTSTART" || name == "DTEND" {
+				if date, clock, ok := splitICalDateTime(value); ok {
+					current[name+".date"] = date
+					current[name+".time"] = clock
+				}
+			}
+		}
+	}
+
+	if len(records) == 0 && !bytes.Contains(bytes.ToUpper(data), []byte("BEGIN:VCALENDAR")) {
+		return nil, fmt.Errorf("Not an iCal document")
+	}
+
+	return records, nil
+}
+
+// unfoldICal joins continuation lines (starting with a space or a tab) to the previous one
+func unfoldICal(data []byte) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) == 0 {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICalLine splits a content line `NAME;PARAM=VALUE:value` into its parts
+func parseICalLine(line string) (string, map[string]string, string, error) {
+	// The value starts at the first colon not inside a quoted parameter value
+	quoted := false
+	separator := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			separator = i
+			break
+		}
+	}
+	if separator < 0 {
+		return "", nil, "", fmt.Errorf("Malformed content line %q", line)
+	}
+
+	parts := strings.Split(line[:separator], ";")
+	name := strings.ToUpper(parts[0])
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], "\"")
+		}
+	}
+
+	return name, params, unescapeICal(line[separator+1:]), nil
+}
+
+func unescapeICal(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// splitICalDateTime returns the date and time of an iCal DATE or DATE-TIME value in Gramola's layouts
+func splitICalDateTime(value string) (string, string, bool) {
+	for _, layout := range []string{icalDateTimeUTCLayout, icalDateTimeLayout, icalDateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			clock := ""
+			if layout != icalDateLayout {
+				clock = t.Format(EventTimeLayout)
+			}
+			return t.Format(EventDateLayout), clock, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,76 @@
+package feed
+
+import (
+	"testing"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+)
+
+const icalFeed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Summer\\, live\r\n" +
+	"ORGANIZER;CN=\"The Band\":mailto:band@example.com\r\n" +
+	"DESCRIPTION:A long description that is\r\n" +
+	"  folded\r\n" +
+	"LOCATION:Arena\r\n" +
+	"DTSTART:20200701T203000Z\r\n" +
+	"DTEND:20200701T233000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Festival\r\n" +
+	"DTSTART;VALUE=DATE:20200801\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	records, err := ParseICal([]byte(icalFeed))
+	if err != nil {
+		t.Fatalf("ParseICal returned an error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("ParseICal returned %d records, expected 2", len(records))
+	}
+
+	expected := Record{
+		"SUMMARY":      "Summer, live",
+		"ORGANIZER.CN": "The Band",
+		"DESCRIPTION":  "A long description that is folded",
+		"DTSTART.date": "2020-07-01",
+		"DTSTART.time": "20:30",
+		"DTEND.time":   "23:30",
+	}
+	for k, v := range expected {
+		if records[0][k] != v {
+			t.Errorf("ParseICal %s is %q, expected %q", k, records[0][k], v)
+		}
+	}
+	if records[1]["DTSTART.date"] != "2020-08-01" || records[1]["DTSTART.time"] != "" {
+		t.Errorf("ParseICal of a DATE value returned %q %q", records[1]["DTSTART.date"], records[1]["DTSTART.time"])
+	}
+
+	event, err := MapRecord(records[0], FieldMapping(gramolav1alpha1.EventFeedFormatICal, nil))
+	if err != nil {
+		t.Fatalf("MapRecord returned an error: %v", err)
+	}
+	if event.Name != "Summer, live" || event.Artist != "The Band" || event.StartDate != "2020-07-01" || event.EndTime != "23:30" {
+		t.Errorf("MapRecord with the iCal defaults mapped %+v", event)
+	}
+}
+
+func TestParseICalErrors(t *testing.T) {
+	tests := map[string]string{
+		"not iCal":       "{\"events\": []}",
+		"malformed line": "BEGIN:VCALENDAR\r\nNO SEPARATOR\r\nEND:VCALENDAR\r\n",
+	}
+	for name, data := range tests {
+		if _, err := ParseICal([]byte(data)); err == nil {
+			t.Errorf("ParseICal of %s didn't return an error", name)
+		}
+	}
+
+	records, err := ParseICal([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	if err != nil || len(records) != 0 {
+		t.Errorf("ParseICal of an empty calendar returned %v, %v", records, err)
+	}
+}
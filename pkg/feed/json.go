@@ -0,0 +1,64 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseJSON returns a Record per object found in the array at itemsPath. Nested
+// objects are flattened, their fields being separated by dots
+func ParseJSON(data []byte, itemsPath string) ([]Record, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("Not a JSON document: %v", err)
+	}
+
+	items := document
+	if len(itemsPath) > 0 {
+		for _, segment := range strings.Split(itemsPath, ".") {
+			object, ok := items.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Items path %s not found in JSON document", itemsPath)
+			}
+			if items, ok = object[segment]; !ok {
+				return nil, fmt.Errorf("Items path %s not found in JSON document", itemsPath)
+			}
+		}
+	}
+
+	entries, ok := items.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Items path '%s' is not an array in JSON document", itemsPath)
+	}
+
+	records := []Record{}
+	for _, entry := range entries {
+		object, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		record := Record{}
+		flattenJSON("", object, record)
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func flattenJSON(prefix string, object map[string]interface{}, record Record) {
+	for k, v := range object {
+		key := prefix + k
+		switch value := v.(type) {
+		case map[string]interface{}:
+			flattenJSON(key+".", value, record)
+		case string:
+			record[key] = value
+		case float64:
+			record[key] = strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			record[key] = strconv.FormatBool(value)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package feed
+
+import (
+	"testing"
+)
+
+func TestParseJSON(t *testing.T) {
+	data := []byte(`{"data": {"items": [
+		{"name": "Concert", "venue": {"city": "Madrid", "capacity": 1500}, "free": false},
+		"not an object",
+		{"name": "Festival"}
+	]}}`)
+
+	records, err := ParseJSON(data, "data.items")
+	if err != nil {
+		t.Fatalf("ParseJSON returned an error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("ParseJSON returned %d records, expected 2", len(records))
+	}
+
+	expected := Record{"name": "Concert", "venue.city": "Madrid", "venue.capacity": "1500", "free": "false"}
+	for k, v := range expected {
+		if records[0][k] != v {
+			t.Errorf("ParseJSON %s is %q, expected %q", k, records[0][k], v)
+		}
+	}
+	if records[1]["name"] != "Festival" {
+		t.Errorf("ParseJSON returned %v as second record", records[1])
+	}
+}
+
+func TestParseJSONTopLevelArray(t *testing.T) {
+	records, err := ParseJSON([]byte(`[{"name": "Concert"}]`), "")
+	if err != nil {
+		t.Fatalf("ParseJSON returned an error: %v", err)
+	}
+	if len(records) != 1 || records[0]["name"] != "Concert" {
+		t.Errorf("ParseJSON returned %v", records)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		itemsPath string
+	}{
+		{"not JSON", "BEGIN:VCALENDAR", ""},
+		{"missing items path", `{"data": {}}`, "data.items"},
+		{"items path through a value", `{"data": "items"}`, "data.items"},
+		{"items not an array", `{"data": {"items": {}}}`, "data.items"},
+	}
+	for _, test := range tests {
+		if _, err := ParseJSON([]byte(test.data), test.itemsPath); err == nil {
+			t.Errorf("ParseJSON of %s didn't return an error", test.name)
+		}
+	}
+}
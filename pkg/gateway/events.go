@@ -0,0 +1,178 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	errors "github.com/pkg/errors"
+)
+
+// Events API paths
+const (
+	EventsPath = "/api/events"
+)
+
+// Event mirrors the Gramola event model as served by the gateway
+type Event struct {
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Artist      string `json:"artist,omitempty"`
+	Description string `json:"description,omitempty"`
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	Province    string `json:"province,omitempty"`
+	Country     string `json:"country,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Image       string `json:"image,omitempty"`
+	Date        string `json:"date,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+	StartTime   string `json:"startTime,omitempty"`
+	EndTime     string `json:"endTime,omitempty"`
+}
+
+// Key returns the natural key used to deduplicate events, the database id is not known by external feeds
+func (e *Event) Key() string {
+	return strings.ToLower(strings.Join([]string{
+		strings.TrimSpace(e.Name),
+		strings.TrimSpace(e.Artist),
+		strings.TrimSpace(e.Location),
+		strings.TrimSpace(e.City),
+		strings.TrimSpace(e.StartDate),
+	}, "|"))
+}
+
+// SameAs returns true if both events hold the same data regardless of their ids
+func (e *Event) SameAs(other *Event) bool {
+	a, b := *e, *other
+	a.ID, b.ID = 0, 0
+	return a == b
+}
+
+// EventsClient talks to the events API exposed by the gateway
+type EventsClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewEventsClient returns an EventsClient given the base URL of a gateway
+func NewEventsClient(baseURL string) *EventsClient {
+	return &EventsClient{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// List returns all the events
+func (c *EventsClient) List() ([]Event, error) {
+	events := []Event{}
+	if err := c.do(http.MethodGet, EventsPath, nil, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Create creates a new event and returns it as stored
+func (c *EventsClient) Create(event *Event) (*Event, error) {
+	created := &Event{}
+	if err := c.do(http.MethodPost, EventsPath, event, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Update replaces the event with the given id
+func (c *EventsClient) Update(id int64, event *Event) (*Event, error) {
+	updated := &Event{}
+	if err := c.do(http.MethodPut, EventsPath+"/"+strconv.FormatInt(id, 10), event, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// UpsertResult tells what Upsert did with an event
+type UpsertResult string
+
+// UpsertResults defined here
+const (
+	UpsertCreated   UpsertResult = "Created"
+	UpsertUpdated   UpsertResult = "Updated"
+	UpsertUnchanged UpsertResult = "Unchanged"
+)
+
+// Upsert creates the event or updates the one in existing with the same Key, existing is updated accordingly
+func (c *EventsClient) Upsert(existing map[string]*Event, event *Event) (UpsertResult, error) {
+	key := event.Key()
+	if current, ok := existing[key]; ok {
+		if current.SameAs(event) {
+			return UpsertUnchanged, nil
+		}
+		updated, err := c.Update(current.ID, event)
+		if err != nil {
+			return "", err
+		}
+		existing[key] = updated
+		return UpsertUpdated, nil
+	}
+
+	created, err := c.Create(event)
+	if err != nil {
+		return "", err
+	}
+	existing[key] = created
+	return UpsertCreated, nil
+}
+
+// IndexByKey returns the events indexed by their natural Key
+func IndexByKey(events []Event) map[string]*Event {
+	index := make(map[string]*Event)
+	for i := range events {
+		index[events[i].Key()] = &events[i]
+	}
+	return index
+}
+
+func (c *EventsClient) do(method string, path string, in interface{}, out interface{}) error {
+	body := &bytes.Buffer{}
+	if in != nil {
+		if err := json.NewEncoder(body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	request, err := http.NewRequest(method, c.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if in != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "Failed calling %s %s", method, path)
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s %s returned %d: %s", method, path, response.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return errors.Wrapf(err, "Failed decoding response from %s %s", method, path)
+		}
+	}
+
+	return nil
+}
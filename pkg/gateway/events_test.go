@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGateway serves the events API from memory and counts the writes it receives
+type fakeGateway struct {
+	sync.Mutex
+	events  map[int64]Event
+	nextID  int64
+	creates int
+	updates int
+}
+
+func (g *fakeGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.Lock()
+	defer g.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == EventsPath:
+		events := []Event{}
+		for _, event := range g.events {
+			events = append(events, event)
+		}
+		json.NewEncoder(w).Encode(events)
+	case r.Method == http.MethodPost && r.URL.Path == EventsPath:
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		g.nextID++
+		event.ID = g.nextID
+		g.events[event.ID] = event
+		g.creates++
+		json.NewEncoder(w).Encode(event)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, EventsPath+"/"):
+		id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, EventsPath+"/"), 10, 64)
+		if _, ok := g.events[id]; err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		event.ID = id
+		g.events[id] = event
+		g.updates++
+		json.NewEncoder(w).Encode(event)
+	default:
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	}
+}
+
+func TestUpsertDeduplicatesByKey(t *testing.T) {
+	gateway := &fakeGateway{events: map[int64]Event{}}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	client := NewEventsClient(server.URL + "/")
+	current, err := client.List()
+	if err != nil {
+		t.Fatalf("List returned an error: %v", err)
+	}
+	existing := IndexByKey(current)
+
+	concert := Event{Name: "Concert", Artist: "Band", Location: "Arena", City: "Madrid", StartDate: "2020-05-01"}
+	steps := []struct {
+		name     string
+		event    Event
+		expected UpsertResult
+	}{
+		{"new event", concert, UpsertCreated},
+		{"same event", concert, UpsertUnchanged},
+		{"same key differently cased", func() Event { e := concert; e.Name = " concert "; e.City = "MADRID"; return e }(), UpsertUpdated},
+		{"new description", func() Event { e := concert; e.Description = "Sold out"; return e }(), UpsertUpdated},
+		{"another date", func() Event { e := concert; e.StartDate = "2020-05-02"; return e }(), UpsertCreated},
+	}
+	for _, step := range steps {
+		event := step.event
+		result, err := client.Upsert(existing, &event)
+		if err != nil {
+			t.Fatalf("Upsert of %s returned an error: %v", step.name, err)
+		}
+		if result != step.expected {
+			t.Errorf("Upsert of %s returned %s, expected %s", step.name, result, step.expected)
+		}
+	}
+
+	if gateway.creates != 2 || gateway.updates != 2 || len(gateway.events) != 2 {
+		t.Errorf("Gateway received %d creates and %d updates and holds %d events, expected 2, 2 and 2",
+			gateway.creates, gateway.updates, len(gateway.events))
+	}
+
+	// A new import starting from the stored events doesn't duplicate them
+	current, err = client.List()
+	if err != nil {
+		t.Fatalf("List returned an error: %v", err)
+	}
+	existing = IndexByKey(current)
+	event := concert
+	event.Description = "Sold out"
+	if result, err := client.Upsert(existing, &event); err != nil || result != UpsertUnchanged {
+		t.Errorf("Upsert of a stored event returned %s, %v", result, err)
+	}
+	if gateway.creates != 2 {
+		t.Errorf("Gateway received %d creates, expected 2", gateway.creates)
+	}
+}
+
+func TestUpsertError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewEventsClient(server.URL)
+	existing := map[string]*Event{}
+	_, err := client.Upsert(existing, &Event{Name: "Concert", StartDate: "2020-05-01"})
+	if err == nil || !strings.Contains(err.Error(), "database unavailable") {
+		t.Errorf("Upsert returned %v, expected the gateway error", err)
+	}
+	if len(existing) != 0 {
+		t.Errorf("Upsert indexed an event that wasn't stored")
+	}
+}
@@ -0,0 +1,105 @@
+package maintenance
+
+import (
+	"context"
+
+	routev1 "github.com/openshift/api/route/v1"
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EnablePage deploys the maintenance page and points the frontend Route to it. The window is recorded in the status
+// of the AppService before the Route is patched, so a page left shown is always hidden again. Returns false if the
+// page was already shown
+func EnablePage(c client.Client, scheme *runtime.Scheme, instance *gramolav1alpha1.AppService, reason gramolav1alpha1.MaintenancePageReason, operation string) (bool, error) {
+	if instance.Status.MaintenancePage != nil && instance.Status.MaintenancePage.Active {
+		return false, nil
+	}
+
+	configMap, err := _deployment.NewMaintenancePageConfigMap(instance, scheme)
+	if err != nil {
+		return false, err
+	}
+	deployment, err := _deployment.NewMaintenancePageDeployment(instance, scheme)
+	if err != nil {
+		return false, err
+	}
+	service, err := _deployment.NewMaintenancePageService(instance, scheme)
+	if err != nil {
+		return false, err
+	}
+	for _, obj := range []runtime.Object{configMap, deployment, service} {
+		if err := c.Create(context.TODO(), obj); err != nil && !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+	}
+
+	patch := client.MergeFrom(instance.DeepCopy())
+	instance.Status.MaintenancePage = &gramolav1alpha1.MaintenancePageStatus{
+		Active:    true,
+		Reason:    reason,
+		Operation: operation,
+		StartTime: metav1.Now(),
+	}
+	if err := c.Status().Patch(context.TODO(), instance, patch); err != nil {
+		return false, err
+	}
+
+	if err := patchFrontendRouteTarget(c, instance, _deployment.GetObjectName(instance, _deployment.MaintenancePageName)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DisablePage points the frontend Route back to the frontend and removes the maintenance page if it was shown
+// for the same reason and operation, the end of the window is recorded in the status of the AppService once the Route
+// is patched. Returns false if there was nothing to do
+func DisablePage(c client.Client, instance *gramolav1alpha1.AppService, reason gramolav1alpha1.MaintenancePageReason, operation string) (bool, error) {
+	page := instance.Status.MaintenancePage
+	if page == nil || !page.Active || page.Reason != reason || page.Operation != operation {
+		return false, nil
+	}
+
+	if err := patchFrontendRouteTarget(c, instance, _deployment.GetObjectName(instance, _deployment.FrontendServiceName)); err != nil {
+		return false, err
+	}
+
+	meta := metav1.ObjectMeta{Name: _deployment.GetObjectName(instance, _deployment.MaintenancePageName), Namespace: instance.Namespace}
+	for _, obj := range []runtime.Object{&appsv1.Deployment{ObjectMeta: meta}, &corev1.Service{ObjectMeta: meta}, &corev1.ConfigMap{ObjectMeta: meta}} {
+		if err := c.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	patch := client.MergeFrom(instance.DeepCopy())
+	now := metav1.Now()
+	page.Active = false
+	page.EndTime = &now
+	if err := c.Status().Patch(context.TODO(), instance, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// patchFrontendRouteTarget points the frontend Route of an AppService to a Service
+func patchFrontendRouteTarget(c client.Client, instance *gramolav1alpha1.AppService, serviceName string) error {
+	route := &routev1.Route{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: _deployment.GetObjectName(instance, _deployment.FrontendServiceName), Namespace: instance.Namespace}, route); err != nil {
+		return err
+	}
+	if route.Spec.To.Name == serviceName {
+		return nil
+	}
+	patch := _deployment.NewFrontendRouteTargetPatch(route, serviceName)
+	return c.Patch(context.TODO(), route, patch)
+}
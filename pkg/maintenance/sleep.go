@@ -0,0 +1,53 @@
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"github.com/robfig/cron/v3"
+)
+
+// sleepSchedules returns the Cron schedules the AppService goes to sleep and wakes up with
+func sleepSchedules(schedule *gramolav1alpha1.SleepSchedule) (cron.Schedule, cron.Schedule, error) {
+	prefix := ""
+	if len(schedule.TimeZone) > 0 {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("Invalid time zone %s of sleep schedule: %v", schedule.TimeZone, err)
+		}
+		prefix = "CRON_TZ=" + schedule.TimeZone + " "
+	}
+	sleep, err := cron.ParseStandard(prefix + schedule.Sleep)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid sleep schedule %s: %v", schedule.Sleep, err)
+	}
+	wake, err := cron.ParseStandard(prefix + schedule.Wake)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid wake schedule %s: %v", schedule.Wake, err)
+	}
+	return sleep, wake, nil
+}
+
+// ValidateSleepSchedule checks the sleep and wake schedules
+func ValidateSleepSchedule(schedule *gramolav1alpha1.SleepSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+	_, _, err := sleepSchedules(schedule)
+	return err
+}
+
+// CheckSleep returns true if the AppService is asleep at now, that is if it wakes up before it goes to sleep again,
+// and the time it goes to sleep or wakes up next
+func CheckSleep(schedule *gramolav1alpha1.SleepSchedule, now time.Time) (bool, time.Time, error) {
+	sleep, wake, err := sleepSchedules(schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	nextSleep, nextWake := sleep.Next(now), wake.Next(now)
+	if nextWake.Before(nextSleep) {
+		return true, nextWake, nil
+	}
+	return false, nextSleep, nil
+}
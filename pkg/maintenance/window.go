@@ -0,0 +1,96 @@
+// Package maintenance computes the maintenance windows of an AppService and shows its maintenance page
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Time zones even if the image lacks them
+	_ "time/tzdata"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"github.com/robfig/cron/v3"
+)
+
+var weekdays = map[gramolav1alpha1.Weekday]string{
+	gramolav1alpha1.Sunday:    "0",
+	gramolav1alpha1.Monday:    "1",
+	gramolav1alpha1.Tuesday:   "2",
+	gramolav1alpha1.Wednesday: "3",
+	gramolav1alpha1.Thursday:  "4",
+	gramolav1alpha1.Friday:    "5",
+	gramolav1alpha1.Saturday:  "6",
+}
+
+// Schedule returns the Cron schedule the window opens with
+func Schedule(window *gramolav1alpha1.MaintenanceWindow) (cron.Schedule, error) {
+	if window.Duration.Duration <= 0 {
+		return nil, fmt.Errorf("Duration of maintenance window must be positive")
+	}
+
+	spec := window.Schedule
+	if len(spec) == 0 {
+		if len(window.Days) == 0 || len(window.Start) == 0 {
+			return nil, fmt.Errorf("Maintenance window needs a schedule or days and a start time")
+		}
+		start, err := time.Parse("15:04", window.Start)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid start time %s of maintenance window: %v", window.Start, err)
+		}
+		days := []string{}
+		for _, day := range window.Days {
+			d, ok := weekdays[day]
+			if !ok {
+				return nil, fmt.Errorf("Invalid day %s of maintenance window", day)
+			}
+			days = append(days, d)
+		}
+		spec = fmt.Sprintf("%d %d * * %s", start.Minute(), start.Hour(), strings.Join(days, ","))
+	} else if len(window.Days) > 0 || len(window.Start) > 0 {
+		return nil, fmt.Errorf("Maintenance window needs either a schedule or days and a start time, not both")
+	}
+
+	if len(window.TimeZone) > 0 {
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			return nil, fmt.Errorf("Invalid time zone %s of maintenance window: %v", window.TimeZone, err)
+		}
+		spec = "CRON_TZ=" + window.TimeZone + " " + spec
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid schedule %s of maintenance window: %v", spec, err)
+	}
+	return schedule, nil
+}
+
+// Validate checks every window
+func Validate(windows []gramolav1alpha1.MaintenanceWindow) error {
+	for i := range windows {
+		if _, err := Schedule(&windows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check returns true if a window is open at now, and the start of that window or of the next one
+func Check(windows []gramolav1alpha1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	var next time.Time
+	for i := range windows {
+		schedule, err := Schedule(&windows[i])
+		if err != nil {
+			return false, next, err
+		}
+		// The window is open if it started less than its duration ago
+		if start := schedule.Next(now.Add(-windows[i].Duration.Duration)); !start.After(now) {
+			return true, start, nil
+		}
+		if start := schedule.Next(now); next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return false, next, nil
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	Register("0.0.2", "Trims and collapses the whitespace of the city, province and country of events", normalizePlaces)
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// normalizePlaces trims the city, province and country of events and collapses their inner whitespace, so events
+// imported from different feeds are grouped by place. Normalized places are left alone, so it can run again
+func normalizePlaces(tx *sql.Tx) error {
+	type place struct {
+		id                      int64
+		city, province, country sql.NullString
+	}
+
+	rows, err := tx.Query("SELECT id, city, province, country FROM public.event")
+	if err != nil {
+		return err
+	}
+	places := []place{}
+	for rows.Next() {
+		p := place{}
+		if err := rows.Scan(&p.id, &p.city, &p.province, &p.country); err != nil {
+			rows.Close()
+			return err
+		}
+		places = append(places, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range places {
+		city, province, country := normalizePlace(p.city), normalizePlace(p.province), normalizePlace(p.country)
+		if city == p.city && province == p.province && country == p.country {
+			continue
+		}
+		if _, err := tx.Exec("UPDATE public.event SET city = $1, province = $2, country = $3 WHERE id = $4", city, province, country, p.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizePlace trims a place and collapses its inner whitespace to single spaces, NULL is kept
+func normalizePlace(name sql.NullString) sql.NullString {
+	if !name.Valid {
+		return name
+	}
+	return sql.NullString{String: spaces.ReplaceAllString(strings.TrimSpace(name.String), " "), Valid: true}
+}
@@ -0,0 +1,56 @@
+// Package migrations holds the data migrations of the events database coded in Go, they run in the same
+// ordered pipeline as the SQL scripts
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	util "github.com/redhat/gramola-operator/pkg/util"
+)
+
+// Func migrates data within tx
+type Func func(tx *sql.Tx) error
+
+// Migration is a data migration coded in Go
+type Migration struct {
+	// Version of the migration, it runs after the scripts of the same version
+	Version string
+	// Description of the migration
+	Description string
+	// Run migrates the data
+	Run Func
+}
+
+// Name returns the name the migration is tracked as, alike the name of the SQL scripts, also in public.operator_version
+func (m *Migration) Name() string {
+	return fmt.Sprintf("events-database-update-%s.go", m.Version)
+}
+
+var registry = map[string]*Migration{}
+
+// Register adds a migration to the registry, it panics if the version is invalid or already registered
+func Register(version string, description string, run Func) {
+	if _, err := util.CompareVersions(version, version); err != nil {
+		panic(fmt.Sprintf("migration %s: %v", version, err))
+	}
+	if _, ok := registry[version]; ok {
+		panic(fmt.Sprintf("migration %s already registered", version))
+	}
+	registry[version] = &Migration{Version: version, Description: description, Run: run}
+}
+
+// Registered returns the registered migrations in version order
+func Registered() []*Migration {
+	registered := []*Migration{}
+	for _, migration := range registry {
+		registered = append(registered, migration)
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		// Versions are checked when registered
+		cmp, _ := util.CompareVersions(registered[i].Version, registered[j].Version)
+		return cmp < 0
+	})
+	return registered
+}
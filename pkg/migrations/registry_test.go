@@ -0,0 +1,101 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// withRegistry runs f with an empty registry and restores the migrations registered by the package afterwards
+func withRegistry(t *testing.T, f func()) {
+	registered := registry
+	registry = map[string]*Migration{}
+	defer func() { registry = registered }()
+	f()
+}
+
+func noop(tx *sql.Tx) error {
+	return nil
+}
+
+func TestRegistered(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expected []string
+	}{
+		{"none", nil, []string{}},
+		{"in order", []string{"0.0.1", "0.0.2"}, []string{"0.0.1", "0.0.2"}},
+		{"numeric order", []string{"0.0.10", "0.0.9", "0.1.0"}, []string{"0.0.9", "0.0.10", "0.1.0"}},
+		{"different lengths", []string{"1", "0.9", "0.10.1"}, []string{"0.9", "0.10.1", "1"}},
+		{"v prefix", []string{"v0.0.3", "0.0.2"}, []string{"0.0.2", "v0.0.3"}},
+	}
+	for _, test := range tests {
+		withRegistry(t, func() {
+			for _, version := range test.versions {
+				Register(version, "", noop)
+			}
+			registered := Registered()
+			if len(registered) != len(test.expected) {
+				t.Fatalf("Registered %s returned %d migrations, expected %d", test.name, len(registered), len(test.expected))
+			}
+			for i, migration := range registered {
+				if migration.Version != test.expected[i] {
+					t.Errorf("Registered %s returned %s at %d, expected %s", test.name, migration.Version, i, test.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+	}{
+		{"duplicated version", []string{"0.0.2", "0.0.2"}},
+		{"invalid version", []string{"0.0.x"}},
+		{"empty version", []string{""}},
+	}
+	for _, test := range tests {
+		withRegistry(t, func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register of a %s didn't panic", test.name)
+				}
+			}()
+			for _, version := range test.versions {
+				Register(version, "", noop)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	migration := &Migration{Version: "0.0.2"}
+	if name := migration.Name(); name != "events-database-update-0.0.2.go" {
+		t.Errorf("Name returned %s", name)
+	}
+}
+
+func TestNormalizePlace(t *testing.T) {
+	tests := []struct {
+		name     sql.NullString
+		expected sql.NullString
+	}{
+		{sql.NullString{}, sql.NullString{}},
+		{sql.NullString{String: "Madrid", Valid: true}, sql.NullString{String: "Madrid", Valid: true}},
+		{sql.NullString{String: "  Madrid\t", Valid: true}, sql.NullString{String: "Madrid", Valid: true}},
+		{sql.NullString{String: "New  York\n City", Valid: true}, sql.NullString{String: "New York City", Valid: true}},
+		{sql.NullString{String: "new york", Valid: true}, sql.NullString{String: "new york", Valid: true}},
+		{sql.NullString{String: " ", Valid: true}, sql.NullString{String: "", Valid: true}},
+	}
+	for _, test := range tests {
+		normalized := normalizePlace(test.name)
+		if normalized != test.expected {
+			t.Errorf("normalizePlace of %+v returned %+v, expected %+v", test.name, normalized, test.expected)
+		}
+		if again := normalizePlace(normalized); again != normalized {
+			t.Errorf("normalizePlace of %+v isn't idempotent, returned %+v", normalized, again)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"strconv"
+	"strings"
 )
 
 // NVL returns def if str is null
@@ -38,3 +40,30 @@ func ReadFile(basePath string, fileName string) (string, error) {
 	}
 	return string(data), nil
 }
+
+// CompareVersions compares two dotted versions like 0.0.2 number by number, missing numbers count as 0. It returns
+// -1, 0 or 1 if a is older than, equal to or newer than b
+func CompareVersions(a string, b string) (int, error) {
+	as, bs := strings.Split(strings.TrimPrefix(a, "v"), "."), strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		an, bn := 0, 0
+		var err error
+		if i < len(as) {
+			if an, err = strconv.Atoi(as[i]); err != nil {
+				return 0, fmt.Errorf("Invalid version %s: %v", a, err)
+			}
+		}
+		if i < len(bs) {
+			if bn, err = strconv.Atoi(bs[i]); err != nil {
+				return 0, fmt.Errorf("Invalid version %s: %v", b, err)
+			}
+		}
+		if an < bn {
+			return -1, nil
+		}
+		if an > bn {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+	_maintenance "github.com/redhat/gramola-operator/pkg/maintenance"
+	"github.com/redhat/gramola-operator/pkg/util"
+	version "github.com/redhat/gramola-operator/version"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Aliases of the AppService
+var aliases = []string{"Gramola", "Gramophone", "Phonograph"}
+
+// Deletion policies of the AppService
+var deletionPolicies = []string{
+	string(gramolav1alpha1.DeletionPolicyDelete),
+	string(gramolav1alpha1.DeletionPolicyRetain),
+	string(gramolav1alpha1.DeletionPolicySnapshot),
+}
+
+// Colours of the branding, #rrggbb
+var colorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Names of CronJobs can't be longer, the Jobs they create are named after them
+const maxCronJobNameLength = 52
+
+// ValidateAppService checks the spec and the annotations of an AppService, it's run by the validating webhook
+// and again by the controller in case the webhook isn't deployed
+func ValidateAppService(instance *gramolav1alpha1.AppService) error {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	errs = append(errs, validateObjectNames(instance)...)
+
+	if len(instance.Spec.Alias) > 0 && !contains(aliases, instance.Spec.Alias) {
+		errs = append(errs, field.NotSupported(spec.Child("alias"), instance.Spec.Alias, aliases))
+	}
+
+	if branding := instance.Spec.Branding; branding != nil {
+		if len(branding.PrimaryColor) > 0 && !colorRegexp.MatchString(branding.PrimaryColor) {
+			errs = append(errs, field.Invalid(spec.Child("branding", "primaryColor"), branding.PrimaryColor, "must be like #rrggbb"))
+		}
+		if len(branding.AccentColor) > 0 && !colorRegexp.MatchString(branding.AccentColor) {
+			errs = append(errs, field.Invalid(spec.Child("branding", "accentColor"), branding.AccentColor, "must be like #rrggbb"))
+		}
+	}
+
+	if database := instance.Spec.Database; database != nil && database.Storage != nil && database.Storage.Sign() <= 0 {
+		errs = append(errs, field.Invalid(spec.Child("database", "storage"), database.Storage.String(), "must be greater than zero"))
+	}
+
+	if retention := instance.Spec.Retention; retention != nil && retention.Days < 0 {
+		errs = append(errs, field.Invalid(spec.Child("retention", "days"), retention.Days, "must not be negative"))
+	}
+
+	if len(instance.Spec.DeletionPolicy) > 0 && !contains(deletionPolicies, string(instance.Spec.DeletionPolicy)) {
+		errs = append(errs, field.NotSupported(spec.Child("deletionPolicy"), instance.Spec.DeletionPolicy, deletionPolicies))
+	}
+	if snapshot := instance.Spec.DeletionSnapshot; snapshot != nil {
+		if err := snapshot.Storage.Validate(); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("deletionSnapshot", "storage"), snapshot.Storage, err.Error()))
+		}
+	}
+
+	if err := _maintenance.Validate(instance.Spec.MaintenanceWindows); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("maintenanceWindows"), instance.Spec.MaintenanceWindows, err.Error()))
+	}
+
+	if err := _maintenance.ValidateSleepSchedule(instance.Spec.Schedule); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("schedule"), instance.Spec.Schedule, err.Error()))
+	}
+
+	if value, ok := instance.Annotations[gramolav1alpha1.WakeUpUntilAnnotation]; ok && len(value) > 0 {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations").Key(gramolav1alpha1.WakeUpUntilAnnotation), value, "must be an RFC 3339 time"))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+// ValidateAppServiceUpdate checks an update of an AppService, the storage class can't change, the storage can't
+// shrink once set, the legacy names annotation can't change once set and the spec can't change if a newer operator
+// reconciled the AppService
+func ValidateAppServiceUpdate(old *gramolav1alpha1.AppService, instance *gramolav1alpha1.AppService) error {
+	errs := field.ErrorList{}
+	database := field.NewPath("spec", "database")
+
+	oldDatabase, newDatabase := old.Spec.Database, instance.Spec.Database
+	if oldDatabase == nil {
+		oldDatabase = &gramolav1alpha1.DatabaseSpec{}
+	}
+	if newDatabase == nil {
+		newDatabase = &gramolav1alpha1.DatabaseSpec{}
+	}
+
+	if oldDatabase.StorageClass != newDatabase.StorageClass {
+		errs = append(errs, field.Forbidden(database.Child("storageClass"),
+			fmt.Sprintf("can't change from %q to %q, the events database volume is already provisioned", oldDatabase.StorageClass, newDatabase.StorageClass)))
+	}
+
+	if oldDatabase.Storage != nil && newDatabase.Storage != nil && newDatabase.Storage.Cmp(*oldDatabase.Storage) < 0 {
+		errs = append(errs, field.Forbidden(database.Child("storage"),
+			fmt.Sprintf("can't shrink from %s to %s", oldDatabase.Storage.String(), newDatabase.Storage.String())))
+	}
+
+	oldLegacyNames, found := old.Annotations[gramolav1alpha1.LegacyNamesAnnotation]
+	if legacyNames := instance.Annotations[gramolav1alpha1.LegacyNamesAnnotation]; found && legacyNames != oldLegacyNames {
+		errs = append(errs, field.Forbidden(field.NewPath("metadata", "annotations").Key(gramolav1alpha1.LegacyNamesAnnotation),
+			fmt.Sprintf("can't change from %q to %q, the objects of the AppService are already named", oldLegacyNames, legacyNames)))
+	}
+
+	if !reflect.DeepEqual(old.Spec, instance.Spec) {
+		if err := ValidateOperatorVersion(old); err != nil {
+			errs = append(errs, field.Forbidden(field.NewPath("spec"), err.Error()))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+// ValidateOperatorVersion checks that the AppService wasn't reconciled by a newer operator, migrations and objects
+// it rolled out can't be taken back by this one
+func ValidateOperatorVersion(instance *gramolav1alpha1.AppService) error {
+	if len(instance.Status.OperatorVersion) == 0 {
+		return nil
+	}
+	cmp, err := util.CompareVersions(instance.Status.OperatorVersion, version.Version)
+	if err != nil {
+		return err
+	}
+	if cmp > 0 {
+		return fmt.Errorf("AppService was reconciled by operator version %s, downgrading to %s is not supported", instance.Status.OperatorVersion, version.Version)
+	}
+	return nil
+}
+
+// contains checks if a string is in a list
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// validateObjectNames checks that the names of the objects of an AppService, prefixed by its name, are valid. Services
+// need DNS-1035 labels and the retention CronJob, the longest name, a short one
+func validateObjectNames(instance *gramolav1alpha1.AppService) field.ErrorList {
+	errs := field.ErrorList{}
+	name := field.NewPath("metadata", "name")
+	if len(instance.Name) == 0 || _deployment.HasLegacyNames(instance) {
+		return errs
+	}
+
+	for _, msg := range validation.IsDNS1035Label(_deployment.GetObjectName(instance, _deployment.EventsDatabaseServiceName)) {
+		errs = append(errs, field.Invalid(name, instance.Name, fmt.Sprintf("prefixes the names of Services: %s", msg)))
+	}
+	if cronJobName := _deployment.GetObjectName(instance, _deployment.EventsDatabaseRetentionCronJobName); len(cronJobName) > maxCronJobNameLength {
+		errs = append(errs, field.TooLong(name, instance.Name, maxCronJobNameLength-len(cronJobName)+len(instance.Name)))
+	}
+	return errs
+}
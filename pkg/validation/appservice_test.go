@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	version "github.com/redhat/gramola-operator/version"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newAppService() *gramolav1alpha1.AppService {
+	return &gramolav1alpha1.AppService{
+		ObjectMeta: metav1.ObjectMeta{Name: "gramola", Namespace: "gramola"},
+		Spec:       gramolav1alpha1.AppServiceSpec{Enabled: true},
+	}
+}
+
+func withStorage(instance *gramolav1alpha1.AppService, storage string, storageClass string) *gramolav1alpha1.AppService {
+	quantity := resource.MustParse(storage)
+	instance.Spec.Database = &gramolav1alpha1.DatabaseSpec{Storage: &quantity, StorageClass: storageClass}
+	return instance
+}
+
+func TestValidateAppService(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(*gramolav1alpha1.AppService)
+		field  string
+	}{
+		{"defaults", func(a *gramolav1alpha1.AppService) {}, ""},
+		{"supported alias", func(a *gramolav1alpha1.AppService) { a.Spec.Alias = "Phonograph" }, ""},
+		{"unsupported alias", func(a *gramolav1alpha1.AppService) { a.Spec.Alias = "Gramolita" }, "spec.alias"},
+		{"name too long", func(a *gramolav1alpha1.AppService) { a.Name = strings.Repeat("g", 40) }, "metadata.name"},
+		{"name not a DNS label", func(a *gramolav1alpha1.AppService) { a.Name = "gramola.events" }, "metadata.name"},
+		{"long name with legacy names", func(a *gramolav1alpha1.AppService) {
+			a.Name = strings.Repeat("g", 40)
+			a.Annotations = map[string]string{gramolav1alpha1.LegacyNamesAnnotation: "true"}
+		}, ""},
+		{"branding colours", func(a *gramolav1alpha1.AppService) {
+			a.Spec.Branding = &gramolav1alpha1.BrandingSpec{PrimaryColor: "#cc0000", AccentColor: "#F0AB00"}
+		}, ""},
+		{"invalid primary colour", func(a *gramolav1alpha1.AppService) {
+			a.Spec.Branding = &gramolav1alpha1.BrandingSpec{PrimaryColor: "red"}
+		}, "spec.branding.primaryColor"},
+		{"invalid accent colour", func(a *gramolav1alpha1.AppService) {
+			a.Spec.Branding = &gramolav1alpha1.BrandingSpec{AccentColor: "#f0ab0"}
+		}, "spec.branding.accentColor"},
+		{"no storage", func(a *gramolav1alpha1.AppService) { withStorage(a, "0", "") }, "spec.database.storage"},
+		{"negative retention", func(a *gramolav1alpha1.AppService) {
+			a.Spec.Retention = &gramolav1alpha1.RetentionSpec{Days: -1}
+		}, "spec.retention.days"},
+		{"unsupported deletion policy", func(a *gramolav1alpha1.AppService) { a.Spec.DeletionPolicy = "Archive" }, "spec.deletionPolicy"},
+		{"deletion snapshot without storage", func(a *gramolav1alpha1.AppService) {
+			a.Spec.DeletionSnapshot = &gramolav1alpha1.DeletionSnapshotSpec{}
+		}, "spec.deletionSnapshot.storage"},
+		{"maintenance window", func(a *gramolav1alpha1.AppService) {
+			a.Spec.MaintenanceWindows = []gramolav1alpha1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}}}
+		}, ""},
+		{"maintenance window without duration", func(a *gramolav1alpha1.AppService) {
+			a.Spec.MaintenanceWindows = []gramolav1alpha1.MaintenanceWindow{{Schedule: "0 2 * * 6"}}
+		}, "spec.maintenanceWindows"},
+		{"invalid sleep schedule", func(a *gramolav1alpha1.AppService) {
+			a.Spec.Schedule = &gramolav1alpha1.SleepSchedule{Sleep: "at night", Wake: "0 8 * * *"}
+		}, "spec.schedule"},
+		{"wake up annotation", func(a *gramolav1alpha1.AppService) {
+			a.Annotations = map[string]string{gramolav1alpha1.WakeUpUntilAnnotation: "2020-05-01T21:00:00Z"}
+		}, ""},
+		{"invalid wake up annotation", func(a *gramolav1alpha1.AppService) {
+			a.Annotations = map[string]string{gramolav1alpha1.WakeUpUntilAnnotation: "tomorrow"}
+		}, "metadata.annotations[" + gramolav1alpha1.WakeUpUntilAnnotation + "]"},
+	}
+
+	for _, test := range tests {
+		instance := newAppService()
+		test.update(instance)
+		err := ValidateAppService(instance)
+		if len(test.field) == 0 && err != nil {
+			t.Errorf("ValidateAppService of %s returned %v", test.name, err)
+		} else if len(test.field) > 0 && (err == nil || !strings.Contains(err.Error(), test.field)) {
+			t.Errorf("ValidateAppService of %s returned %v, expected an error in %s", test.name, err, test.field)
+		}
+	}
+}
+
+func TestValidateAppServiceUpdate(t *testing.T) {
+	legacyNames := func(value string) func(*gramolav1alpha1.AppService) {
+		return func(a *gramolav1alpha1.AppService) {
+			a.Annotations = map[string]string{gramolav1alpha1.LegacyNamesAnnotation: value}
+		}
+	}
+	reconciledBy := func(operatorVersion string) func(*gramolav1alpha1.AppService) {
+		return func(a *gramolav1alpha1.AppService) { a.Status.OperatorVersion = operatorVersion }
+	}
+
+	tests := []struct {
+		name   string
+		old    func(*gramolav1alpha1.AppService)
+		update func(*gramolav1alpha1.AppService)
+		field  string
+	}{
+		{"storage class set", func(a *gramolav1alpha1.AppService) {}, func(a *gramolav1alpha1.AppService) {
+			withStorage(a, "1Gi", "gp2")
+		}, "spec.database.storageClass"},
+		{"storage class changed", func(a *gramolav1alpha1.AppService) { withStorage(a, "1Gi", "gp2") }, func(a *gramolav1alpha1.AppService) {
+			withStorage(a, "1Gi", "io1")
+		}, "spec.database.storageClass"},
+		{"storage class kept", func(a *gramolav1alpha1.AppService) { withStorage(a, "1Gi", "gp2") }, func(a *gramolav1alpha1.AppService) {
+			withStorage(a, "1Gi", "gp2")
+		}, ""},
+		{"storage grown", func(a *gramolav1alpha1.AppService) { withStorage(a, "1Gi", "") }, func(a *gramolav1alpha1.AppService) {
+			withStorage(a, "2Gi", "")
+		}, ""},
+		{"storage shrunk", func(a *gramolav1alpha1.AppService) { withStorage(a, "1Gi", "") }, func(a *gramolav1alpha1.AppService) {
+			withStorage(a, "512Mi", "")
+		}, "spec.database.storage"},
+		{"storage set", func(a *gramolav1alpha1.AppService) {}, func(a *gramolav1alpha1.AppService) {
+			withStorage(a, "512Mi", "")
+		}, ""},
+		{"legacy names annotation set", func(a *gramolav1alpha1.AppService) {}, legacyNames("true"), ""},
+		{"legacy names annotation changed", legacyNames("false"), legacyNames("true"), "metadata.annotations[" + gramolav1alpha1.LegacyNamesAnnotation + "]"},
+		{"legacy names annotation removed", legacyNames("true"), func(a *gramolav1alpha1.AppService) {
+			a.Annotations = nil
+		}, "metadata.annotations[" + gramolav1alpha1.LegacyNamesAnnotation + "]"},
+		{"spec changed, reconciled by this operator", reconciledBy(version.Version), func(a *gramolav1alpha1.AppService) {
+			a.Spec.Enabled = false
+		}, ""},
+		{"spec changed, reconciled by a newer operator", reconciledBy("99.0.0"), func(a *gramolav1alpha1.AppService) {
+			a.Spec.Enabled = false
+		}, "spec"},
+		{"metadata changed, reconciled by a newer operator", reconciledBy("99.0.0"), func(a *gramolav1alpha1.AppService) {
+			a.Finalizers = []string{gramolav1alpha1.AppServiceFinalizer}
+		}, ""},
+	}
+
+	for _, test := range tests {
+		old := newAppService()
+		test.old(old)
+		instance := old.DeepCopy()
+		test.update(instance)
+		err := ValidateAppServiceUpdate(old, instance)
+		if len(test.field) == 0 && err != nil {
+			t.Errorf("ValidateAppServiceUpdate of %s returned %v", test.name, err)
+		} else if len(test.field) > 0 && (err == nil || !strings.Contains(err.Error(), test.field)) {
+			t.Errorf("ValidateAppServiceUpdate of %s returned %v, expected an error in %s", test.name, err, test.field)
+		}
+	}
+}
+
+func TestValidateOperatorVersion(t *testing.T) {
+	tests := map[string]bool{
+		"":              true,
+		"0.0.1":         true,
+		version.Version: true,
+		"99.0.0":        false,
+		"99":            false,
+		"next":          false,
+	}
+	for operatorVersion, valid := range tests {
+		instance := newAppService()
+		instance.Status.OperatorVersion = operatorVersion
+		if err := ValidateOperatorVersion(instance); (err == nil) != valid {
+			t.Errorf("ValidateOperatorVersion of %q returned %v", operatorVersion, err)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// ConversionWebhookPath is the path the conversion webhook of the CRDs is served at
+const ConversionWebhookPath = "/convert"
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create webhooks and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, addConversion)
+}
+
+// addConversion registers the webhook converting the versions of the CRDs, e.g. AppService v1alpha1 and v1beta1
+func addConversion(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(ConversionWebhookPath, &conversion.Webhook{})
+	return nil
+}
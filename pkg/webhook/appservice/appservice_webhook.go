@@ -0,0 +1,116 @@
+package appservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_validation "github.com/redhat/gramola-operator/pkg/validation"
+
+	"k8s.io/api/admission/v1beta1"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Paths the AppService webhooks are served at
+const (
+	MutatingWebhookPath   = "/mutate-gramola-redhat-com-v1alpha1-appservice"
+	ValidatingWebhookPath = "/validate-gramola-redhat-com-v1alpha1-appservice"
+)
+
+var log = logf.Log.WithName("webhook-appservice")
+
+// Add registers the AppService webhooks in the webhook server of the Manager
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(MutatingWebhookPath, &webhook.Admission{Handler: &appServiceDefaulter{}})
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: &appServiceValidator{}})
+	return nil
+}
+
+// appServiceDefaulter fills in the defaults of AppServices, so the controller never has to update their spec
+type appServiceDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder
+func (d *appServiceDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle sets the defaults of AppServices being created or updated, fields no longer in the type such as
+// spec.initialized are dropped as the AppService is encoded again
+func (d *appServiceDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	instance := &gramolav1alpha1.AppService{}
+	if err := d.decoder.Decode(req, instance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	setDefaults(instance)
+
+	marshaled, err := json.Marshal(instance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// appServiceValidator rejects AppServices the controller would fail to reconcile
+type appServiceValidator struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder
+func (v *appServiceValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates AppServices being created or updated. AppServices being deleted are let go, and updates of their
+// metadata only, such as the finalizer or the annotations the controller writes, aren't checked against rules that
+// AppServices created before them may not pass
+func (v *appServiceValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	instance := &gramolav1alpha1.AppService{}
+	if err := v.decoder.Decode(req, instance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if instance.DeletionTimestamp != nil {
+		return admission.Allowed("being deleted")
+	}
+
+	old := &gramolav1alpha1.AppService{}
+	if req.Operation == v1beta1.Update {
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	if req.Operation != v1beta1.Update || needsValidation(old, instance) {
+		if err := _validation.ValidateAppService(instance); err != nil {
+			log.Info("Denied AppService", "name", instance.Name, "namespace", instance.Namespace, "reason", err.Error())
+			return admission.Denied(err.Error())
+		}
+	}
+
+	if req.Operation == v1beta1.Update {
+		if err := _validation.ValidateAppServiceUpdate(old, instance); err != nil {
+			log.Info("Denied AppService update", "name", instance.Name, "namespace", instance.Namespace, "reason", err.Error())
+			return admission.Denied(err.Error())
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+// needsValidation checks if an update changes the spec or the wake up annotation, the fields ValidateAppService
+// checks. The legacy names annotation, written by the controller, only relaxes the checks of the names
+func needsValidation(old *gramolav1alpha1.AppService, instance *gramolav1alpha1.AppService) bool {
+	return !reflect.DeepEqual(old.Spec, instance.Spec) ||
+		old.Annotations[gramolav1alpha1.WakeUpUntilAnnotation] != instance.Annotations[gramolav1alpha1.WakeUpUntilAnnotation]
+}
@@ -0,0 +1,98 @@
+package appservice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/redhat/gramola-operator/pkg/apis"
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newValidator(t *testing.T) *appServiceValidator {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatalf("Unable to build the scheme: %v", err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatalf("Unable to build the decoder: %v", err)
+	}
+	validator := &appServiceValidator{}
+	validator.InjectDecoder(decoder)
+	return validator
+}
+
+func newAppService(alias string) *gramolav1alpha1.AppService {
+	return &gramolav1alpha1.AppService{
+		TypeMeta:   metav1.TypeMeta{APIVersion: gramolav1alpha1.SchemeGroupVersion.String(), Kind: "AppService"},
+		ObjectMeta: metav1.ObjectMeta{Name: "gramola", Namespace: "gramola"},
+		Spec:       gramolav1alpha1.AppServiceSpec{Enabled: true, Alias: alias},
+	}
+}
+
+func raw(t *testing.T, instance *gramolav1alpha1.AppService) runtime.RawExtension {
+	data, err := json.Marshal(instance)
+	if err != nil {
+		t.Fatalf("Unable to marshal the AppService: %v", err)
+	}
+	return runtime.RawExtension{Raw: data}
+}
+
+func TestValidatorHandle(t *testing.T) {
+	// An AppService created before the validating webhook, its alias is no longer supported
+	invalid := newAppService("Gramolita")
+
+	tests := []struct {
+		name    string
+		old     *gramolav1alpha1.AppService
+		update  func(*gramolav1alpha1.AppService)
+		allowed bool
+	}{
+		{"create of an invalid AppService", nil, func(a *gramolav1alpha1.AppService) {}, false},
+		{"create of a valid AppService", nil, func(a *gramolav1alpha1.AppService) { a.Spec.Alias = "Gramola" }, true},
+		{"finalizer added", invalid, func(a *gramolav1alpha1.AppService) {
+			a.Finalizers = []string{gramolav1alpha1.AppServiceFinalizer}
+		}, true},
+		{"legacy names annotation added", invalid, func(a *gramolav1alpha1.AppService) {
+			a.Annotations = map[string]string{gramolav1alpha1.LegacyNamesAnnotation: "true"}
+		}, true},
+		{"finalizer removed while being deleted", invalid, func(a *gramolav1alpha1.AppService) {
+			now := metav1.Now()
+			a.DeletionTimestamp = &now
+			a.Finalizers = nil
+			a.Spec.Database = &gramolav1alpha1.DatabaseSpec{StorageClass: "gp2"}
+		}, true},
+		{"spec changed", invalid, func(a *gramolav1alpha1.AppService) { a.Spec.Enabled = false }, false},
+		{"wake up annotation changed", invalid, func(a *gramolav1alpha1.AppService) {
+			a.Annotations = map[string]string{gramolav1alpha1.WakeUpUntilAnnotation: "tomorrow"}
+		}, false},
+		{"storage class changed", newAppService("Gramola"), func(a *gramolav1alpha1.AppService) {
+			a.Spec.Database = &gramolav1alpha1.DatabaseSpec{StorageClass: "gp2"}
+		}, false},
+	}
+
+	validator := newValidator(t)
+	for _, test := range tests {
+		req := admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{Operation: v1beta1.Create}}
+		instance := invalid.DeepCopy()
+		if test.old != nil {
+			req.Operation = v1beta1.Update
+			req.OldObject = raw(t, test.old)
+			instance = test.old.DeepCopy()
+		}
+		test.update(instance)
+		req.Object = raw(t, instance)
+
+		response := validator.Handle(context.TODO(), req)
+		if response.Allowed != test.allowed {
+			t.Errorf("Handle of %s returned allowed %t, expected %t: %v", test.name, response.Allowed, test.allowed, response.Result)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package appservice
+
+import (
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+	_deployment "github.com/redhat/gramola-operator/pkg/deployment"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// setDefaults fills in the defaults the controller assumes for empty fields, so that they show in the AppService.
+// Replicas and images aren't in the spec: images come with the operator version, pinning them in the spec would stop
+// upgrades from rolling out new ones, and replicas are scaled by spec.enabled and spec.schedule
+func setDefaults(instance *gramolav1alpha1.AppService) {
+	if len(instance.Spec.Alias) == 0 {
+		instance.Spec.Alias = _deployment.DefaultAlias
+	}
+
+	if len(instance.Spec.DeletionPolicy) == 0 {
+		instance.Spec.DeletionPolicy = gramolav1alpha1.DeletionPolicyDelete
+	}
+
+	if instance.Spec.Database == nil {
+		instance.Spec.Database = &gramolav1alpha1.DatabaseSpec{}
+	}
+	database := instance.Spec.Database
+	if database.Storage == nil {
+		storage := resource.MustParse(_deployment.EventsDatabaseDefaultStorage)
+		database.Storage = &storage
+	}
+	if len(database.MigrationApproval) == 0 {
+		database.MigrationApproval = gramolav1alpha1.MigrationApprovalAutomatic
+	}
+	if len(database.ReadOnly) == 0 {
+		database.ReadOnly = gramolav1alpha1.ReadOnlyModeNever
+	}
+
+	if instance.Spec.Retention != nil && len(instance.Spec.Retention.Schedule) == 0 {
+		instance.Spec.Retention.Schedule = _deployment.EventsDatabaseRetentionSchedule
+	}
+}
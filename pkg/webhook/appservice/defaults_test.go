@@ -0,0 +1,109 @@
+package appservice
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redhat/gramola-operator/pkg/apis"
+	gramolav1alpha1 "github.com/redhat/gramola-operator/pkg/apis/gramola/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestSetDefaults(t *testing.T) {
+	storage := resource.MustParse("512Mi")
+	defaulted := gramolav1alpha1.AppServiceSpec{
+		Enabled:        true,
+		Alias:          "Gramola",
+		DeletionPolicy: gramolav1alpha1.DeletionPolicyDelete,
+		Database: &gramolav1alpha1.DatabaseSpec{
+			Storage:           &storage,
+			MigrationApproval: gramolav1alpha1.MigrationApprovalAutomatic,
+			ReadOnly:          gramolav1alpha1.ReadOnlyModeNever,
+		},
+	}
+
+	setStorage := resource.MustParse("2Gi")
+	set := gramolav1alpha1.AppServiceSpec{
+		Alias:          "Phonograph",
+		DeletionPolicy: gramolav1alpha1.DeletionPolicyRetain,
+		Database: &gramolav1alpha1.DatabaseSpec{
+			Storage:           &setStorage,
+			StorageClass:      "gp2",
+			MigrationApproval: gramolav1alpha1.MigrationApprovalManual,
+			ReadOnly:          gramolav1alpha1.ReadOnlyModeAlways,
+		},
+		Retention: &gramolav1alpha1.RetentionSpec{Days: 7, Schedule: "0 1 * * *"},
+	}
+
+	tests := []struct {
+		name     string
+		spec     gramolav1alpha1.AppServiceSpec
+		expected gramolav1alpha1.AppServiceSpec
+	}{
+		{"empty spec", gramolav1alpha1.AppServiceSpec{Enabled: true}, defaulted},
+		{"defaulted spec", defaulted, defaulted},
+		{"retention", gramolav1alpha1.AppServiceSpec{Enabled: true, Retention: &gramolav1alpha1.RetentionSpec{Days: 30}},
+			func() gramolav1alpha1.AppServiceSpec {
+				spec := *defaulted.DeepCopy()
+				spec.Retention = &gramolav1alpha1.RetentionSpec{Days: 30, Schedule: "0 3 * * *"}
+				return spec
+			}()},
+		{"values set", set, set},
+	}
+
+	for _, test := range tests {
+		instance := &gramolav1alpha1.AppService{Spec: *test.spec.DeepCopy()}
+		setDefaults(instance)
+		if !reflect.DeepEqual(instance.Spec, test.expected) {
+			t.Errorf("setDefaults of %s returned %+v, expected %+v", test.name, instance.Spec, test.expected)
+		}
+	}
+}
+
+func TestDefaulterHandle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatalf("Unable to build the scheme: %v", err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatalf("Unable to build the decoder: %v", err)
+	}
+	defaulter := &appServiceDefaulter{}
+	defaulter.InjectDecoder(decoder)
+
+	// An AppService created by a former operator, spec.initialized is no longer in the type
+	object := []byte(`{"apiVersion":"gramola.redhat.com/v1alpha1","kind":"AppService","metadata":{"name":"gramola"},` +
+		`"spec":{"enabled":true,"initialized":true,"alias":"Gramophone"}}`)
+	response := defaulter.Handle(context.TODO(), admission.Request{AdmissionRequest: v1beta1.AdmissionRequest{
+		Operation: v1beta1.Update,
+		Object:    runtime.RawExtension{Raw: object},
+	}})
+	if !response.Allowed {
+		t.Fatalf("Handle denied the AppService: %v", response.Result)
+	}
+
+	paths := map[string]string{}
+	for _, operation := range response.Patches {
+		if strings.HasPrefix(operation.Path, "/spec/") {
+			paths[operation.Path] = operation.Operation
+		}
+	}
+	expected := map[string]string{
+		"/spec/initialized":    "remove",
+		"/spec/deletionPolicy": "add",
+		"/spec/database":       "add",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		patches, _ := json.Marshal(response.Patches)
+		t.Errorf("Handle patched %s, expected %v", patches, expected)
+	}
+}